@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/spf13/viper"
 )
@@ -18,12 +20,87 @@ var (
 		field.WithRequired(true),
 	)
 
+	FlagPrivilegedWithoutMFAField = field.BoolField(
+		"flag-privileged-without-mfa",
+		field.WithDescription("Annotate owner and admin organization grants held by users without 2FA enabled."),
+		field.WithDefaultValue(false),
+	)
+
+	WebhookBufferPathField = field.StringField(
+		"webhook-buffer-path",
+		field.WithDescription("Path of the file webhook events are buffered in for the event feed."),
+	)
+
+	IncrementalStateDirField = field.StringField(
+		"incremental-state-dir",
+		field.WithDescription("Directory the memberships seen by the previous sync and event feed call are kept in. When set, org and space grants only fetch the memberships updated since."),
+	)
+
+	MaxConcurrencyField = field.IntField(
+		"max-concurrency",
+		field.WithDescription("The maximum number of spaces whose roles and members are fetched at the same time."),
+		field.WithDefaultValue(4),
+	)
+
+	RoleBaselinePathField = field.StringField(
+		"role-baseline-path",
+		field.WithDescription("Path of a YAML or JSON file of canonical space roles. Space role entitlements that drift from it are annotated."),
+	)
+
+	RiskRulesPathField = field.StringField(
+		"risk-rules-path",
+		field.WithDescription("Path of a YAML or JSON file of the rules entitlements are classified by risk with, in place of the default rules."),
+	)
+
+	WebhookSecretField = field.StringField(
+		"webhook-secret",
+		field.WithDescription("The signing secret of the Contentful webhook."),
+		field.WithIsSecret(true),
+		field.WithRequired(true),
+	)
+
+	WebhookListenAddressField = field.StringField(
+		"webhook-listen-address",
+		field.WithDescription("The address the webhook receiver listens on."),
+		field.WithDefaultValue(":8080"),
+	)
+
 	// ConfigurationFields defines the external configuration required for the
 	// connector to run. Note: these fields can be marked as optional or
 	// required.
 	ConfigurationFields = []field.SchemaField{
 		TokenField,
 		OrgIdField,
+		FlagPrivilegedWithoutMFAField,
+		WebhookBufferPathField,
+		IncrementalStateDirField,
+		MaxConcurrencyField,
+		RoleBaselinePathField,
+		RiskRulesPathField,
+	}
+
+	// WebhookReceiverFields are the flags of the webhook-receiver command.
+	WebhookReceiverFields = []field.SchemaField{
+		TokenField,
+		OrgIdField,
+		WebhookSecretField,
+		WebhookListenAddressField,
+		field.StringField(
+			WebhookBufferPathField.FieldName,
+			field.WithDescription("Path of the file webhook events are buffered in for the event feed."),
+			field.WithRequired(true),
+		),
+	}
+
+	// RoleDriftFields are the flags of the role-drift command.
+	RoleDriftFields = []field.SchemaField{
+		TokenField,
+		OrgIdField,
+		field.StringField(
+			RoleBaselinePathField.FieldName,
+			field.WithDescription("Path of a YAML or JSON file of canonical space roles."),
+			field.WithRequired(true),
+		),
 	}
 
 	// FieldRelationships defines relationships between the fields listed in
@@ -38,5 +115,8 @@ var (
 // needs to perform extra validations that cannot be encoded with configuration
 // parameters.
 func ValidateConfig(v *viper.Viper) error {
+	if v.GetInt(MaxConcurrencyField.FieldName) < 1 {
+		return fmt.Errorf("%s must be at least 1", MaxConcurrencyField.FieldName)
+	}
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/conductorone/baton-contentful/pkg/connector"
+	"github.com/conductorone/baton-sdk/pkg/cli"
 	"github.com/conductorone/baton-sdk/pkg/config"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/field"
@@ -20,7 +21,7 @@ var version = "dev"
 func main() {
 	ctx := context.Background()
 
-	_, cmd, err := config.DefineConfiguration(
+	v, cmd, err := config.DefineConfiguration(
 		ctx,
 		"baton-contentful",
 		getConnector,
@@ -35,6 +36,18 @@ func main() {
 
 	cmd.Version = version
 
+	_, err = cli.AddCommand(cmd, v, &field.Configuration{Fields: WebhookReceiverFields}, newWebhookReceiverCommand(ctx, v))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	_, err = cli.AddCommand(cmd, v, &field.Configuration{Fields: RoleDriftFields}, newRoleDriftCommand(ctx, v))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
 	err = cmd.Execute()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
@@ -48,10 +61,16 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 		return nil, err
 	}
 
-	cb, err := connector.New(ctx,
-		v.GetString(OrgIdField.FieldName),
-		v.GetString(TokenField.FieldName),
-	)
+	cb, err := connector.New(ctx, connector.Config{
+		OrgID:                    v.GetString(OrgIdField.FieldName),
+		Token:                    v.GetString(TokenField.FieldName),
+		FlagPrivilegedWithoutMFA: v.GetBool(FlagPrivilegedWithoutMFAField.FieldName),
+		WebhookBufferPath:        v.GetString(WebhookBufferPathField.FieldName),
+		IncrementalStateDir:      v.GetString(IncrementalStateDirField.FieldName),
+		RoleBaselinePath:         v.GetString(RoleBaselinePathField.FieldName),
+		RiskRulesPath:            v.GetString(RiskRulesPathField.FieldName),
+		MaxConcurrency:           v.GetInt(MaxConcurrencyField.FieldName),
+	})
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
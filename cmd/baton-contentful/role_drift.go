@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-contentful/pkg/connector"
+	"github.com/conductorone/baton-sdk/pkg/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newRoleDriftCommand returns the command that compares the roles of every space to a baseline of canonical roles
+// and prints how they differ, one line per difference.
+func newRoleDriftCommand(ctx context.Context, v *viper.Viper) *cobra.Command {
+	return &cobra.Command{
+		Use:   "role-drift",
+		Short: "Report space roles that drift from a baseline of canonical roles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logLevel := v.GetString("log-level")
+			if logLevel == "" {
+				logLevel = "info"
+			}
+			ctx, err := logging.Init(ctx, logging.WithLogFormat(logging.LogFormatJSON), logging.WithLogLevel(logLevel))
+			if err != nil {
+				return err
+			}
+
+			cb, err := connector.New(ctx, connector.Config{
+				OrgID:            v.GetString(OrgIdField.FieldName),
+				Token:            v.GetString(TokenField.FieldName),
+				RoleBaselinePath: v.GetString(RoleBaselinePathField.FieldName),
+				MaxConcurrency:   1,
+			})
+			if err != nil {
+				return fmt.Errorf("error creating connector: %w", err)
+			}
+
+			drifts, err := cb.RoleDrift(ctx)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, drift := range drifts {
+				for _, warning := range drift.Warnings {
+					fmt.Fprintf(out, "%s (%s)\t%s\t%s\n", drift.SpaceName, drift.SpaceID, drift.Role, warning)
+				}
+			}
+			if len(drifts) > 0 {
+				return fmt.Errorf("%d space roles drift from the baseline", len(drifts))
+			}
+
+			fmt.Fprintln(out, "no space role drifts from the baseline")
+			return nil
+		},
+	}
+}
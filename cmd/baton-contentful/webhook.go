@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/conductorone/baton-contentful/pkg/connector"
+	"github.com/conductorone/baton-contentful/pkg/webhook"
+	"github.com/conductorone/baton-sdk/pkg/logging"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// newWebhookReceiverCommand returns the command that receives Contentful webhooks and buffers them
+// for the event feed, the connector reads the buffer when started with the same --webhook-buffer-path.
+func newWebhookReceiverCommand(ctx context.Context, v *viper.Viper) *cobra.Command {
+	return &cobra.Command{
+		Use:   "webhook-receiver",
+		Short: "Receive Contentful webhooks and buffer them as connector events",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logLevel := v.GetString("log-level")
+			if logLevel == "" {
+				logLevel = "info"
+			}
+			ctx, err := logging.Init(ctx, logging.WithLogFormat(logging.LogFormatJSON), logging.WithLogLevel(logLevel))
+			if err != nil {
+				return err
+			}
+			l := ctxzap.Extract(ctx)
+
+			secret := v.GetString(WebhookSecretField.FieldName)
+			addr := v.GetString(WebhookListenAddressField.FieldName)
+			bufferPath := v.GetString(WebhookBufferPathField.FieldName)
+
+			cb, err := connector.New(ctx, connector.Config{
+				OrgID:             v.GetString(OrgIdField.FieldName),
+				Token:             v.GetString(TokenField.FieldName),
+				WebhookBufferPath: bufferPath,
+				MaxConcurrency:    1,
+			})
+			if err != nil {
+				return fmt.Errorf("error creating connector: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			server := &http.Server{
+				Addr:              addr,
+				Handler:           webhook.NewReceiver(webhook.NewVerifier(secret), cb.WebhookEvents, cb.WebhookBuffer()),
+				ReadHeaderTimeout: 10 * time.Second,
+				BaseContext: func(net.Listener) context.Context {
+					return ctx
+				},
+			}
+
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if err := server.Shutdown(shutdownCtx); err != nil {
+					l.Error("error shutting down webhook receiver", zap.Error(err))
+				}
+			}()
+
+			l.Info("webhook receiver listening", zap.String("address", addr), zap.String("buffer", bufferPath))
+			err = server.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,368 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: pb/contentful/v1/annotations.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PrivilegedWithoutMFA annotates the owner and admin grants of an organization held by users without 2FA enabled.
+type PrivilegedWithoutMFA struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warning       string                 `protobuf:"bytes,1,opt,name=warning,proto3" json:"warning,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrivilegedWithoutMFA) Reset() {
+	*x = PrivilegedWithoutMFA{}
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrivilegedWithoutMFA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrivilegedWithoutMFA) ProtoMessage() {}
+
+func (x *PrivilegedWithoutMFA) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrivilegedWithoutMFA.ProtoReflect.Descriptor instead.
+func (*PrivilegedWithoutMFA) Descriptor() ([]byte, []int) {
+	return file_pb_contentful_v1_annotations_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PrivilegedWithoutMFA) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+// Invitation is the org invitation created for an account.
+type Invitation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	InvitationUrl string                 `protobuf:"bytes,2,opt,name=invitation_url,json=invitationUrl,proto3" json:"invitation_url,omitempty"`
+	// the user the invitation was sent to, empty when Contentful didn't return it
+	UserId string `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// the pending membership backing the invitation, deleting it revokes the invitation
+	OrganizationMembershipId string `protobuf:"bytes,4,opt,name=organization_membership_id,json=organizationMembershipId,proto3" json:"organization_membership_id,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Invitation) Reset() {
+	*x = Invitation{}
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Invitation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Invitation) ProtoMessage() {}
+
+func (x *Invitation) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Invitation.ProtoReflect.Descriptor instead.
+func (*Invitation) Descriptor() ([]byte, []int) {
+	return file_pb_contentful_v1_annotations_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Invitation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Invitation) GetInvitationUrl() string {
+	if x != nil {
+		return x.InvitationUrl
+	}
+	return ""
+}
+
+func (x *Invitation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Invitation) GetOrganizationMembershipId() string {
+	if x != nil {
+		return x.OrganizationMembershipId
+	}
+	return ""
+}
+
+// BaselineDrift annotates the entitlement of a space role whose policies drift from the baseline role of the same name.
+type BaselineDrift struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// one per missing, extra or diverging policy
+	Differences   []string `protobuf:"bytes,1,rep,name=differences,proto3" json:"differences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BaselineDrift) Reset() {
+	*x = BaselineDrift{}
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BaselineDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaselineDrift) ProtoMessage() {}
+
+func (x *BaselineDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaselineDrift.ProtoReflect.Descriptor instead.
+func (*BaselineDrift) Descriptor() ([]byte, []int) {
+	return file_pb_contentful_v1_annotations_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BaselineDrift) GetDifferences() []string {
+	if x != nil {
+		return x.Differences
+	}
+	return nil
+}
+
+// RiskClassification annotates the entitlements a risk rule classifies.
+type RiskClassification struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the risk level of the rule, such as high
+	Risk       string `protobuf:"bytes,1,opt,name=risk,proto3" json:"risk,omitempty"`
+	Privileged bool   `protobuf:"varint,2,opt,name=privileged,proto3" json:"privileged,omitempty"`
+	// name of the first rule that matched the entitlement
+	Rule          string `protobuf:"bytes,3,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskClassification) Reset() {
+	*x = RiskClassification{}
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskClassification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskClassification) ProtoMessage() {}
+
+func (x *RiskClassification) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskClassification.ProtoReflect.Descriptor instead.
+func (*RiskClassification) Descriptor() ([]byte, []int) {
+	return file_pb_contentful_v1_annotations_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RiskClassification) GetRisk() string {
+	if x != nil {
+		return x.Risk
+	}
+	return ""
+}
+
+func (x *RiskClassification) GetPrivileged() bool {
+	if x != nil {
+		return x.Privileged
+	}
+	return false
+}
+
+func (x *RiskClassification) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+// PendingInvitation annotates the grants of invitees who haven't accepted their org invitation yet.
+type PendingInvitation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warning       string                 `protobuf:"bytes,1,opt,name=warning,proto3" json:"warning,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingInvitation) Reset() {
+	*x = PendingInvitation{}
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingInvitation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingInvitation) ProtoMessage() {}
+
+func (x *PendingInvitation) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_contentful_v1_annotations_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingInvitation.ProtoReflect.Descriptor instead.
+func (*PendingInvitation) Descriptor() ([]byte, []int) {
+	return file_pb_contentful_v1_annotations_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PendingInvitation) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+var File_pb_contentful_v1_annotations_proto protoreflect.FileDescriptor
+
+const file_pb_contentful_v1_annotations_proto_rawDesc = "" +
+	"\n" +
+	"\"pb/contentful/v1/annotations.proto\x12\rcontentful.v1\"0\n" +
+	"\x14PrivilegedWithoutMFA\x12\x18\n" +
+	"\awarning\x18\x01 \x01(\tR\awarning\"\x9a\x01\n" +
+	"\n" +
+	"Invitation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0einvitation_url\x18\x02 \x01(\tR\rinvitationUrl\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12<\n" +
+	"\x1aorganization_membership_id\x18\x04 \x01(\tR\x18organizationMembershipId\"1\n" +
+	"\rBaselineDrift\x12 \n" +
+	"\vdifferences\x18\x01 \x03(\tR\vdifferences\"\\\n" +
+	"\x12RiskClassification\x12\x12\n" +
+	"\x04risk\x18\x01 \x01(\tR\x04risk\x12\x1e\n" +
+	"\n" +
+	"privileged\x18\x02 \x01(\bR\n" +
+	"privileged\x12\x12\n" +
+	"\x04rule\x18\x03 \x01(\tR\x04rule\"-\n" +
+	"\x11PendingInvitation\x12\x18\n" +
+	"\awarning\x18\x01 \x01(\tR\awarningB;Z9github.com/conductorone/baton-contentful/pb/contentful/v1b\x06proto3"
+
+var (
+	file_pb_contentful_v1_annotations_proto_rawDescOnce sync.Once
+	file_pb_contentful_v1_annotations_proto_rawDescData []byte
+)
+
+func file_pb_contentful_v1_annotations_proto_rawDescGZIP() []byte {
+	file_pb_contentful_v1_annotations_proto_rawDescOnce.Do(func() {
+		file_pb_contentful_v1_annotations_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pb_contentful_v1_annotations_proto_rawDesc), len(file_pb_contentful_v1_annotations_proto_rawDesc)))
+	})
+	return file_pb_contentful_v1_annotations_proto_rawDescData
+}
+
+var file_pb_contentful_v1_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_pb_contentful_v1_annotations_proto_goTypes = []any{
+	(*PrivilegedWithoutMFA)(nil), // 0: contentful.v1.PrivilegedWithoutMFA
+	(*Invitation)(nil),           // 1: contentful.v1.Invitation
+	(*BaselineDrift)(nil),        // 2: contentful.v1.BaselineDrift
+	(*RiskClassification)(nil),   // 3: contentful.v1.RiskClassification
+	(*PendingInvitation)(nil),    // 4: contentful.v1.PendingInvitation
+}
+var file_pb_contentful_v1_annotations_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pb_contentful_v1_annotations_proto_init() }
+func file_pb_contentful_v1_annotations_proto_init() {
+	if File_pb_contentful_v1_annotations_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pb_contentful_v1_annotations_proto_rawDesc), len(file_pb_contentful_v1_annotations_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pb_contentful_v1_annotations_proto_goTypes,
+		DependencyIndexes: file_pb_contentful_v1_annotations_proto_depIdxs,
+		MessageInfos:      file_pb_contentful_v1_annotations_proto_msgTypes,
+	}.Build()
+	File_pb_contentful_v1_annotations_proto = out.File
+	file_pb_contentful_v1_annotations_proto_goTypes = nil
+	file_pb_contentful_v1_annotations_proto_depIdxs = nil
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
@@ -12,18 +13,53 @@ const defaultLimit = 100
 
 type Client struct {
 	*uhttp.BaseHttpClient
-	orgID string
-	token string
+	baseURL   string
+	orgID     string
+	token     string
+	transport func(http.RoundTripper) http.RoundTripper
 }
 
-func New(ctx context.Context, orgID, token string) (*Client, error) {
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL sends the requests to another host than BaseURL, e.g. a fake Contentful in tests.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTransport sends the requests through the round tripper wrap returns for the client's own, which
+// authenticates them, e.g. one recording or replaying the exchanges with Contentful in tests.
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = wrap
+	}
+}
+
+func New(ctx context.Context, orgID, token string, opts ...Option) (*Client, error) {
+	rv := &Client{
+		baseURL: BaseURL,
+		orgID:   orgID,
+		token:   token,
+	}
+	for _, opt := range opts {
+		opt(rv)
+	}
+
 	client, err := uhttp.NewBearerAuth(token).GetClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
-	return &Client{
-		BaseHttpClient: uhttp.NewBaseHttpClient(client),
-		orgID:          orgID,
-		token:          token,
-	}, nil
+	if rv.transport != nil {
+		client.Transport = rv.transport(client.Transport)
+	}
+
+	rv.BaseHttpClient = uhttp.NewBaseHttpClient(client)
+	return rv, nil
+}
+
+// OrgID returns the ID of the organization the client is scoped to.
+func (c *Client) OrgID() string {
+	return c.orgID
 }
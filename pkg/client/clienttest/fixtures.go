@@ -0,0 +1,276 @@
+package clienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FixtureOrgID is the organization ID recorded fixtures have in place of the one they were recorded from.
+const FixtureOrgID = "fixture-org"
+
+const redacted = "REDACTED"
+
+var (
+	// emails in queries have their @ escaped
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+(@|%40)[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// the API keys carry their delivery and preview tokens
+	tokenPattern = regexp.MustCompile(`"(accessToken|token)"(\s*):(\s*)"[^"]*"`)
+)
+
+// Interaction is an exchange with Contentful, as recorded in a fixture file.
+type Interaction struct {
+	Method string `json:"method"`
+	// path and query of the request
+	URL         string          `json:"url"`
+	StatusCode  int             `json:"statusCode"`
+	ContentType string          `json:"contentType,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// Fixtures records the exchanges of a client with Contentful to a fixture file, or replays them from it. Pass its
+// Transport to client.WithTransport.
+type Fixtures struct {
+	path string
+	// the organization ID and token scrubbed from recordings, empty when replaying
+	orgID string
+	token string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	// index of the interactions already replayed
+	replayed map[int]bool
+	// email: the placeholder it was scrubbed to
+	emails map[string]string
+	// kind of value, such as first name: value: the placeholder it was scrubbed to
+	placeholders map[string]map[string]string
+}
+
+// Record records the exchanges of a client of the organization orgID with Contentful, and writes them to the fixture
+// file at path when the test finishes, unless it failed. The token, the organization ID, the email addresses, the
+// names and avatars of users and the names of spaces are scrubbed from them.
+func Record(t testing.TB, path, orgID, token string) *Fixtures {
+	f := &Fixtures{
+		path:         path,
+		orgID:        orgID,
+		token:        token,
+		emails:       make(map[string]string),
+		placeholders: make(map[string]map[string]string),
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			return
+		}
+		if err := f.write(); err != nil {
+			t.Errorf("clienttest: failed to write fixtures: %v", err)
+		}
+	})
+	return f
+}
+
+// Replay serves the exchanges of the fixture file at path to a client of the organization FixtureOrgID, without
+// network access. Requests are matched on their method, path and query, those recorded more than once are served in
+// the order they were recorded.
+func Replay(t testing.TB, path string) *Fixtures {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("clienttest: failed to read fixtures: %v", err)
+	}
+
+	f := &Fixtures{
+		path:     path,
+		replayed: make(map[int]bool),
+	}
+	if err := json.Unmarshal(data, &f.interactions); err != nil {
+		t.Fatalf("clienttest: failed to parse fixtures %s: %v", path, err)
+	}
+	return f
+}
+
+// Transport wraps the transport of a client, next is only called when recording.
+func (f *Fixtures) Transport(next http.RoundTripper) http.RoundTripper {
+	if f.replayed != nil {
+		return roundTripper(f.replay)
+	}
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		return f.record(next, req)
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req)
+}
+
+func (f *Fixtures) replay(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	url := req.URL.RequestURI()
+	for i, interaction := range f.interactions {
+		if f.replayed[i] || interaction.Method != req.Method || interaction.URL != url {
+			continue
+		}
+		f.replayed[i] = true
+
+		header := make(http.Header)
+		if interaction.ContentType != "" {
+			header.Set("Content-Type", interaction.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(interaction.Body)),
+			ContentLength: int64(len(interaction.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("clienttest: no fixture left in %s for %s %s", f.path, req.Method, url)
+}
+
+func (f *Fixtures) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	interaction := Interaction{
+		Method:      req.Method,
+		URL:         f.scrub(req.URL.RequestURI()),
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if len(body) > 0 {
+		interaction.Body, err = f.scrubBody(body)
+		if err != nil {
+			return nil, fmt.Errorf("clienttest: %s %s answered with a body that is not JSON", req.Method, interaction.URL)
+		}
+	}
+	f.interactions = append(f.interactions, interaction)
+
+	return resp, nil
+}
+
+// scrub replaces the token, the organization ID and the email addresses in s. An email address is scrubbed to the
+// same placeholder wherever it appears in the recording.
+func (f *Fixtures) scrub(s string) string {
+	if f.token != "" {
+		s = strings.ReplaceAll(s, f.token, redacted)
+	}
+	if f.orgID != "" {
+		s = strings.ReplaceAll(s, f.orgID, FixtureOrgID)
+	}
+	s = tokenPattern.ReplaceAllString(s, `"$1"$2:$3"`+redacted+`"`)
+	return emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		at := emailPattern.FindStringSubmatch(email)[1]
+		email = strings.Replace(email, at, "@", 1)
+		placeholder, ok := f.emails[email]
+		if !ok {
+			placeholder = fmt.Sprintf("user%d@example.com", len(f.emails)+1)
+			f.emails[email] = placeholder
+		}
+		return strings.Replace(placeholder, "@", at, 1)
+	})
+}
+
+// scrubBody scrubs a JSON body like any string, then replaces the names and avatars of users and the names of
+// spaces in it.
+func (f *Fixtures) scrubBody(body []byte) (json.RawMessage, error) {
+	decoder := json.NewDecoder(strings.NewReader(f.scrub(string(body))))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(f.scrubValue(doc)); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimSpace(buf.Bytes())), nil
+}
+
+func (f *Fixtures) scrubValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			s, isString := value.(string)
+			switch {
+			case !isString || s == "":
+				v[key] = f.scrubValue(value)
+			case key == "firstName":
+				v[key] = f.placeholder("First name", s)
+			case key == "lastName":
+				v[key] = f.placeholder("Last name", s)
+			case key == "avatarUrl":
+				v[key] = redacted
+			}
+		}
+		if sys, ok := v["sys"].(map[string]any); ok && sys["type"] == "Space" {
+			if name, ok := v["name"].(string); ok && name != "" {
+				v["name"] = f.placeholder("Space", name)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = f.scrubValue(v[i])
+		}
+	}
+	return v
+}
+
+// placeholder returns the placeholder of a value of the kind, the same value has the same placeholder wherever it
+// appears in the recording.
+func (f *Fixtures) placeholder(kind, value string) string {
+	values, ok := f.placeholders[kind]
+	if !ok {
+		values = make(map[string]string)
+		f.placeholders[kind] = values
+	}
+
+	placeholder, ok := values[value]
+	if !ok {
+		placeholder = fmt.Sprintf("%s %d", kind, len(values)+1)
+		values[value] = placeholder
+	}
+	return placeholder
+}
+
+func (f *Fixtures) write() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.MarshalIndent(f.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,111 @@
+package clienttest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFixturesRecordAndReplay(t *testing.T) {
+	s := NewServer(t, "org-1")
+	s.Users = []client.User{
+		{FirstName: "Ada", LastName: "Lovelace", AvatarURL: "https://www.gravatar.com/avatar/ada", Email: "ada@acme.test", Sys: client.SystemInfo{ID: "user-1"}},
+		{FirstName: "Grace", Email: "grace@acme.test", Sys: client.SystemInfo{ID: "user-2"}},
+	}
+	s.Spaces = []client.Space{{Name: "Blog", Sys: client.SystemInfo{Type: "Space", ID: "space-1", Org: client.Link{Sys: client.LinkSys{ID: "org-1"}}}}}
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+
+	t.Run("record", func(t *testing.T) {
+		fixtures := Record(t, path, "org-1", Token)
+		c, err := client.New(context.Background(), "org-1", Token, client.WithBaseURL(s.URL), client.WithTransport(fixtures.Transport))
+		require.NoError(t, err)
+
+		users, err := c.ListUsers(context.Background(), 0)
+		require.NoError(t, err)
+		require.Equal(t, "ada@acme.test", users.Items[0].Email)
+		_, err = c.ListSpaces(context.Background(), 0)
+		require.NoError(t, err)
+		_, err = c.GetUser(context.Background(), "user-3")
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"org-1", Token, "ada@acme.test", "grace@acme.test", "Ada", "Lovelace", "gravatar", "Grace", "Blog"} {
+		require.NotContains(t, string(data), secret)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		fixtures := Replay(t, path)
+		c, err := client.New(context.Background(), FixtureOrgID, "any-token", client.WithTransport(fixtures.Transport))
+		require.NoError(t, err)
+
+		users, err := c.ListUsers(context.Background(), 0)
+		require.NoError(t, err)
+		require.Len(t, users.Items, 2)
+		require.Equal(t, "user-1", users.Items[0].Sys.ID)
+		require.Equal(t, "user1@example.com", users.Items[0].Email)
+		require.Equal(t, "user2@example.com", users.Items[1].Email)
+		require.Equal(t, "First name 1", users.Items[0].FirstName)
+		require.Equal(t, "Last name 1", users.Items[0].LastName)
+		require.Equal(t, "REDACTED", users.Items[0].AvatarURL)
+		require.Equal(t, "First name 2", users.Items[1].FirstName)
+
+		spaces, err := c.ListSpaces(context.Background(), 0)
+		require.NoError(t, err)
+		require.Equal(t, FixtureOrgID, spaces.Items[0].Sys.Org.Sys.ID)
+		require.Equal(t, "Space 1", spaces.Items[0].Name)
+
+		_, err = c.GetUser(context.Background(), "user-3")
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		// every fixture is served once
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.contentful.com/spaces?limit=100&skip=0", nil)
+		require.NoError(t, err)
+		_, err = fixtures.Transport(nil).RoundTrip(req)
+		require.ErrorContains(t, err, "no fixture left")
+	})
+}
+
+func TestFixturesScrub(t *testing.T) {
+	f := Record(t, filepath.Join(t.TempDir(), "fixtures.json"), "org-1", "secret-token")
+
+	require.Equal(t,
+		`{"organization":"fixture-org","email":"user1@example.com","accessToken": "REDACTED","token":"REDACTED"}`,
+		f.scrub(`{"organization":"org-1","email":"ada@acme.test","accessToken": "delivery-token","token":"secret-token"}`),
+	)
+	require.Equal(t, "/organizations/fixture-org/users?query=user2%40example.com", f.scrub("/organizations/org-1/users?query=grace%40acme.test"))
+	// the same email has the same placeholder wherever it appears
+	require.Equal(t, `"Ada <user1@example.com>"`, f.scrub(`"Ada <ada@acme.test>"`))
+}
+
+func TestFixturesScrubBody(t *testing.T) {
+	f := Record(t, filepath.Join(t.TempDir(), "fixtures.json"), "org-1", "secret-token")
+
+	body, err := f.scrubBody([]byte(`{"items": [
+		{"firstName": "Ada", "lastName": "Lovelace", "avatarUrl": "https://www.gravatar.com/avatar/ada", "sys": {"type": "User", "version": 7}},
+		{"name": "Blog", "sys": {"type": "Space", "organization": {"sys": {"id": "org-1"}}}},
+		{"name": "Editor", "sys": {"type": "Role", "space": {"sys": {"type": "Link", "linkType": "Space"}}}}
+	]}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items": [
+		{"firstName": "First name 1", "lastName": "Last name 1", "avatarUrl": "REDACTED", "sys": {"type": "User", "version": 7}},
+		{"name": "Space 1", "sys": {"type": "Space", "organization": {"sys": {"id": "fixture-org"}}}},
+		{"name": "Editor", "sys": {"type": "Role", "space": {"sys": {"type": "Link", "linkType": "Space"}}}}
+	]}`, string(body))
+
+	// the same name has the same placeholder wherever it appears
+	body, err = f.scrubBody([]byte(`{"firstName": "Ada", "lastName": "Byron"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"firstName": "First name 1", "lastName": "Last name 2"}`, string(body))
+
+	_, err = f.scrubBody([]byte(`<html>`))
+	require.Error(t, err)
+}
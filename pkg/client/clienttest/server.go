@@ -0,0 +1,877 @@
+// Package clienttest serves a fake Contentful from memory, or the exchanges recorded from the real one, so the client
+// and the connector can be tested without network access.
+package clienttest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+)
+
+// Token is the only access token the server accepts.
+const Token = "clienttest-token"
+
+const (
+	contentType  = "application/vnd.contentful.management.v1+json"
+	defaultLimit = 100
+	maxLimit     = 1000
+
+	notFound = "The resource could not be found."
+)
+
+// errorIDs are the sys.id of the error payloads Contentful sends with each status code.
+var errorIDs = map[int]string{
+	http.StatusBadRequest:          "BadRequest",
+	http.StatusUnauthorized:        "AccessTokenInvalid",
+	http.StatusForbidden:           "AccessDenied",
+	http.StatusNotFound:            "NotFound",
+	http.StatusConflict:            "VersionMismatch",
+	http.StatusUnprocessableEntity: "ValidationFailed",
+	http.StatusTooManyRequests:     "RateLimitExceeded",
+	http.StatusInternalServerError: "InternalServerError",
+}
+
+// Server is a fake of the organization, user, team, space, role and membership endpoints of Contentful for the
+// organization OrgID, plus the environment endpoints the connector lists. Lists are paginated with limit and skip,
+// updates are rejected unless they carry the current sys.version, and failures come with the error payload of
+// Contentful.
+//
+// The exported fields are what the server serves, requests that create, update or delete change them. Lock the
+// server to read or change them while requests can be served.
+type Server struct {
+	*httptest.Server
+	sync.Mutex
+
+	OrgID string
+	// caps the number of items of a page below the requested limit, unless 0
+	PageSize int
+
+	Organizations []client.Organization
+	// organizationId: identity provider, organizations without one have no SSO
+	IdentityProviders       map[string]client.IdentityProvider
+	Users                   []client.User
+	OrganizationMemberships []client.OrganizationMembership
+	Teams                   []client.Team
+	TeamMemberships         []client.TeamMembership
+	Spaces                  []client.Space
+	// spaceId: roles
+	Roles            map[string][]client.Role
+	SpaceMemberships []client.SpaceMembership
+	// spaceId: environments
+	Environments map[string][]client.Environment
+	// spaceId/environmentId: locales
+	Locales map[string][]client.Locale
+	// spaceId/environmentId: content types
+	ContentTypes map[string][]client.ContentType
+	// spaceId: environment aliases
+	EnvironmentAliases map[string][]client.EnvironmentAlias
+	// spaceId: delivery API keys
+	APIKeys map[string][]client.APIKey
+
+	// "METHOD path": status code
+	failures map[string]int
+	// email: user ID of invitees without a user
+	invitees map[string]string
+	nextID   int
+}
+
+// NewServer starts a server for the organization, it is closed when the test ends.
+func NewServer(t testing.TB, orgID string) *Server {
+	s := &Server{
+		OrgID:              orgID,
+		IdentityProviders:  make(map[string]client.IdentityProvider),
+		Roles:              make(map[string][]client.Role),
+		Environments:       make(map[string][]client.Environment),
+		Locales:            make(map[string][]client.Locale),
+		ContentTypes:       make(map[string][]client.ContentType),
+		EnvironmentAliases: make(map[string][]client.EnvironmentAlias),
+		APIKeys:            make(map[string][]client.APIKey),
+		failures:           make(map[string]int),
+		invitees:           make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	s.route(mux)
+	s.Server = httptest.NewServer(s.handle(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a client of the server's organization. The SDK caches the responses of GET requests, the client
+// can list what the server served before a change, so check changes on the fields of the server.
+func (s *Server) Client(t testing.TB) *client.Client {
+	c, err := client.New(context.Background(), s.OrgID, Token, client.WithBaseURL(s.URL))
+	if err != nil {
+		t.Fatalf("clienttest: failed to create client: %v", err)
+	}
+	return c
+}
+
+// Fail makes every request with the method to the path fail with the status code and its error payload.
+func (s *Server) Fail(method, path string, statusCode int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.failures[method+" "+path] = statusCode
+}
+
+// handle authenticates the requests and serves them one at a time.
+func (s *Server) handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+Token {
+			writeError(w, http.StatusUnauthorized, "The access token you sent could not be found or is invalid.")
+			return
+		}
+
+		s.Lock()
+		defer s.Unlock()
+
+		if statusCode, ok := s.failures[r.Method+" "+r.URL.Path]; ok {
+			writeError(w, statusCode, http.StatusText(statusCode))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) route(mux *http.ServeMux) {
+	mux.HandleFunc("GET /organizations", s.listOrganizations)
+	mux.HandleFunc("GET /organizations/{org}/identity_provider", s.getIdentityProvider)
+	mux.HandleFunc("GET /organizations/{org}/users", s.inOrg(s.listUsers))
+	mux.HandleFunc("GET /organizations/{org}/users/{user}", s.inOrg(s.getUser))
+	mux.HandleFunc("POST /organizations/{org}/invitations", s.inOrg(s.createInvitation))
+	mux.HandleFunc("GET /organizations/{org}/organization_memberships", s.inOrg(s.listOrganizationMemberships))
+	mux.HandleFunc("PUT /organizations/{org}/organization_memberships/{membership}", s.inOrg(s.updateOrganizationMembership))
+	mux.HandleFunc("DELETE /organizations/{org}/organization_memberships/{membership}", s.inOrg(s.deleteOrganizationMembership))
+	mux.HandleFunc("GET /organizations/{org}/teams", s.inOrg(s.listTeams))
+	mux.HandleFunc("POST /organizations/{org}/teams", s.inOrg(s.createTeam))
+	mux.HandleFunc("DELETE /organizations/{org}/teams/{team}", s.inOrg(s.deleteTeam))
+	mux.HandleFunc("GET /organizations/{org}/team_memberships", s.inOrg(s.listTeamMemberships))
+	mux.HandleFunc("POST /organizations/{org}/teams/{team}/team_memberships", s.inOrg(s.createTeamMembership))
+	mux.HandleFunc("DELETE /organizations/{org}/teams/{team}/team_memberships/{membership}", s.inOrg(s.deleteTeamMembership))
+	mux.HandleFunc("GET /organizations/{org}/space_memberships", s.inOrg(s.listOrganizationSpaceMemberships))
+
+	mux.HandleFunc("GET /spaces", s.listSpaces)
+	mux.HandleFunc("POST /spaces", s.createSpace)
+	mux.HandleFunc("GET /spaces/{space}/roles", s.inSpace(s.listRoles))
+	mux.HandleFunc("POST /spaces/{space}/roles", s.inSpace(s.createRole))
+	mux.HandleFunc("PUT /spaces/{space}/roles/{role}", s.inSpace(s.updateRole))
+	mux.HandleFunc("GET /spaces/{space}/space_members", s.inSpace(s.listSpaceMemberships))
+	mux.HandleFunc("POST /spaces/{space}/space_memberships", s.inSpace(s.createSpaceMembership))
+	mux.HandleFunc("PUT /spaces/{space}/space_memberships/{membership}", s.inSpace(s.updateSpaceMembership))
+	mux.HandleFunc("DELETE /spaces/{space}/space_memberships/{membership}", s.inSpace(s.deleteSpaceMembership))
+	mux.HandleFunc("GET /spaces/{space}/environments", s.inSpace(s.listEnvironments))
+	mux.HandleFunc("GET /spaces/{space}/environments/{env}/locales", s.inSpace(s.listLocales))
+	mux.HandleFunc("GET /spaces/{space}/environments/{env}/content_types", s.inSpace(s.listContentTypes))
+	mux.HandleFunc("GET /spaces/{space}/environment_aliases", s.inSpace(s.listEnvironmentAliases))
+	mux.HandleFunc("GET /spaces/{space}/api_keys", s.inSpace(s.listAPIKeys))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, notFound)
+	})
+}
+
+// inOrg only serves requests for the server's organization.
+func (s *Server) inOrg(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("org") != s.OrgID {
+			writeError(w, http.StatusNotFound, notFound)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// inSpace only serves requests for the spaces of the server.
+func (s *Server) inSpace(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spaceID := r.PathValue("space")
+		if !slices.ContainsFunc(s.Spaces, func(space client.Space) bool { return space.Sys.ID == spaceID }) {
+			writeError(w, http.StatusNotFound, notFound)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.Organizations)
+}
+
+func (s *Server) getIdentityProvider(w http.ResponseWriter, r *http.Request) {
+	idp, ok := s.IdentityProviders[r.PathValue("org")]
+	if !ok {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, idp)
+}
+
+// listUsers matches the query against the ID, email and names of the users, like the search of Contentful.
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	users := filter(s.Users, func(user client.User) bool {
+		if query == "" {
+			return true
+		}
+		for _, value := range []string{user.Sys.ID, user.Email, user.FirstName, user.LastName} {
+			if strings.Contains(strings.ToLower(value), query) {
+				return true
+			}
+		}
+		return false
+	})
+	writePage(w, r, s.PageSize, users)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.Users, func(user client.User) bool { return user.Sys.ID == r.PathValue("user") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Users[i])
+}
+
+// createInvitation adds a pending org membership for the email, of its user when there is one. An email that is
+// already pending gets a new invitation.
+func (s *Server) createInvitation(w http.ResponseWriter, r *http.Request) {
+	var body client.CreateInvitationBody
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Email == "" {
+		writeError(w, http.StatusUnprocessableEntity, "email is required")
+		return
+	}
+
+	role := body.Role
+	if role == "" {
+		role = "member"
+	}
+
+	userID := s.userIDByEmail(body.Email)
+	i := slices.IndexFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool { return userID != "" && m.Sys.User.Sys.ID == userID })
+	switch {
+	case i >= 0 && s.OrganizationMemberships[i].Sys.Status != "pending":
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s is already a member of the organization", body.Email))
+		return
+	case i >= 0:
+		// inviting a pending invitee again sends a new invitation with the role it names
+		s.OrganizationMemberships[i].Role = role
+	default:
+		if userID == "" {
+			userID = s.newID("user")
+			s.invitees[strings.ToLower(body.Email)] = userID
+		}
+
+		orgMembership := client.OrganizationMembership{
+			Role: role,
+			Sys:  s.newSys("OrganizationMembership", "org-membership"),
+		}
+		orgMembership.Sys.Status = "pending"
+		orgMembership.Sys.User = link("User", userID)
+		s.OrganizationMemberships = append(s.OrganizationMemberships, orgMembership)
+		i = len(s.OrganizationMemberships) - 1
+	}
+	orgMembership := s.OrganizationMemberships[i]
+
+	invitation := client.Invitation{
+		Sys: s.newSys("Invitation", "invitation"),
+	}
+	invitation.Sys.User = link("User", userID)
+	invitation.Sys.OrganizationMembership = link("OrganizationMembership", orgMembership.Sys.ID)
+	invitation.Sys.InvitationURL = fmt.Sprintf("%s/invitations/%s", s.URL, invitation.Sys.ID)
+	writeJSON(w, http.StatusCreated, invitation)
+}
+
+func (s *Server) listOrganizationMemberships(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, ok := readSince(w, r)
+	if !ok {
+		return
+	}
+
+	memberships := filter(s.OrganizationMemberships, func(m client.OrganizationMembership) bool {
+		return matches(query, "role", m.Role) &&
+			matches(query, "sys.user.sys.id[eq]", m.Sys.User.Sys.ID) &&
+			!m.Sys.UpdatedAt.Before(since)
+	})
+	if query.Get("include") != "sys.user" {
+		writePage(w, r, s.PageSize, memberships)
+		return
+	}
+
+	writeIncludedPage(w, r, s.PageSize, memberships, func(page []client.OrganizationMembership) any {
+		var users []client.User
+		for _, m := range page {
+			if user, ok := s.userByID(m.Sys.User.Sys.ID); ok {
+				users = append(users, user)
+			}
+		}
+		return map[string][]client.User{"User": users}
+	})
+}
+
+func (s *Server) updateOrganizationMembership(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool { return m.Sys.ID == r.PathValue("membership") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	var body client.OrganizationMembership
+	if !readJSON(w, r, &body) || !checkVersion(w, r, &s.OrganizationMemberships[i].Sys) {
+		return
+	}
+
+	s.OrganizationMemberships[i].Role = body.Role
+	s.OrganizationMemberships[i].IsExemptFromRestrictedMode = body.IsExemptFromRestrictedMode
+	writeJSON(w, http.StatusOK, s.OrganizationMemberships[i])
+}
+
+// deleteOrganizationMembership also removes the team and space memberships of the user, like Contentful does.
+func (s *Server) deleteOrganizationMembership(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool { return m.Sys.ID == r.PathValue("membership") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	orgMembership := s.OrganizationMemberships[i]
+	s.OrganizationMemberships = slices.Delete(s.OrganizationMemberships, i, i+1)
+	s.TeamMemberships = slices.DeleteFunc(s.TeamMemberships, func(m client.TeamMembership) bool {
+		return m.Sys.OrganizationMembership.Sys.ID == orgMembership.Sys.ID
+	})
+	s.SpaceMemberships = slices.DeleteFunc(s.SpaceMemberships, func(m client.SpaceMembership) bool {
+		return m.Sys.User.Sys.ID == orgMembership.Sys.User.Sys.ID
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listTeams(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.Teams)
+}
+
+func (s *Server) createTeam(w http.ResponseWriter, r *http.Request) {
+	var body client.Team
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name is required")
+		return
+	}
+	if slices.ContainsFunc(s.Teams, func(team client.Team) bool { return team.Name == body.Name }) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("a team named %s already exists", body.Name))
+		return
+	}
+
+	team := client.Team{
+		Name:        body.Name,
+		Description: body.Description,
+		Sys:         s.newSys("Team", "team"),
+	}
+	s.Teams = append(s.Teams, team)
+	writeJSON(w, http.StatusCreated, team)
+}
+
+func (s *Server) deleteTeam(w http.ResponseWriter, r *http.Request) {
+	teamID := r.PathValue("team")
+	i := slices.IndexFunc(s.Teams, func(team client.Team) bool { return team.Sys.ID == teamID })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	s.Teams = slices.Delete(s.Teams, i, i+1)
+	s.TeamMemberships = slices.DeleteFunc(s.TeamMemberships, func(m client.TeamMembership) bool { return m.Sys.Team.Sys.ID == teamID })
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listTeamMemberships(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	memberships := filter(s.TeamMemberships, func(m client.TeamMembership) bool {
+		return matches(query, "sys.organizationMembership.sys.id", m.Sys.OrganizationMembership.Sys.ID)
+	})
+	writePage(w, r, s.PageSize, memberships)
+}
+
+func (s *Server) createTeamMembership(w http.ResponseWriter, r *http.Request) {
+	teamID := r.PathValue("team")
+	if !slices.ContainsFunc(s.Teams, func(team client.Team) bool { return team.Sys.ID == teamID }) {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	var body struct {
+		OrganizationMembershipID string `json:"organizationMembershipId"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	i := slices.IndexFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool { return m.Sys.ID == body.OrganizationMembershipID })
+	if i < 0 {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("organization membership %s does not exist", body.OrganizationMembershipID))
+		return
+	}
+	if slices.ContainsFunc(s.TeamMemberships, func(m client.TeamMembership) bool {
+		return m.Sys.Team.Sys.ID == teamID && m.Sys.OrganizationMembership.Sys.ID == body.OrganizationMembershipID
+	}) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("organization membership %s is already a member of team %s", body.OrganizationMembershipID, teamID))
+		return
+	}
+
+	teamMembership := client.TeamMembership{
+		Sys: s.newSys("TeamMembership", "team-membership"),
+	}
+	teamMembership.Sys.Team = link("Team", teamID)
+	teamMembership.Sys.User = s.OrganizationMemberships[i].Sys.User
+	teamMembership.Sys.OrganizationMembership = link("OrganizationMembership", body.OrganizationMembershipID)
+	s.TeamMemberships = append(s.TeamMemberships, teamMembership)
+	writeJSON(w, http.StatusCreated, teamMembership)
+}
+
+func (s *Server) deleteTeamMembership(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.TeamMemberships, func(m client.TeamMembership) bool {
+		return m.Sys.ID == r.PathValue("membership") && m.Sys.Team.Sys.ID == r.PathValue("team")
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	s.TeamMemberships = slices.Delete(s.TeamMemberships, i, i+1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listOrganizationSpaceMemberships(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, ok := readSince(w, r)
+	if !ok {
+		return
+	}
+
+	memberships := filter(s.SpaceMemberships, func(m client.SpaceMembership) bool {
+		return matches(query, "sys.space.sys.id[eq]", m.Sys.Space.Sys.ID) &&
+			matches(query, "sys.user.sys.id[eq]", m.Sys.User.Sys.ID) &&
+			!m.Sys.UpdatedAt.Before(since)
+	})
+	writePage(w, r, s.PageSize, memberships)
+}
+
+// listSpaces lists every space, the spaces of other organizations the token can see too.
+func (s *Server) listSpaces(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.Spaces)
+}
+
+func (s *Server) createSpace(w http.ResponseWriter, r *http.Request) {
+	orgID := r.Header.Get("X-Contentful-Organization")
+	if orgID != s.OrgID {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("organization %s could not be found", orgID))
+		return
+	}
+
+	var body struct {
+		Name          string `json:"name"`
+		DefaultLocale string `json:"defaultLocale"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name is required")
+		return
+	}
+
+	space := client.Space{
+		Name: body.Name,
+		Sys:  s.newSys("Space", "space"),
+	}
+	space.Sys.Org = link("Organization", s.OrgID)
+	s.Spaces = append(s.Spaces, space)
+	writeJSON(w, http.StatusCreated, space)
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.Roles[r.PathValue("space")])
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	spaceID := r.PathValue("space")
+
+	var body client.Role
+	if !readJSON(w, r, &body) || !s.validRole(w, spaceID, "", body) {
+		return
+	}
+
+	role := body
+	role.Sys = s.newSys("Role", "role")
+	role.Sys.Space = link("Space", spaceID)
+	s.Roles[spaceID] = append(s.Roles[spaceID], role)
+	writeJSON(w, http.StatusCreated, role)
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *http.Request) {
+	spaceID := r.PathValue("space")
+	roles := s.Roles[spaceID]
+	i := slices.IndexFunc(roles, func(role client.Role) bool { return role.Sys.ID == r.PathValue("role") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	var body client.Role
+	if !readJSON(w, r, &body) || !s.validRole(w, spaceID, roles[i].Sys.ID, body) || !checkVersion(w, r, &roles[i].Sys) {
+		return
+	}
+
+	roles[i].Name = body.Name
+	roles[i].Description = body.Description
+	roles[i].Policies = body.Policies
+	roles[i].Permissions = body.Permissions
+	writeJSON(w, http.StatusOK, roles[i])
+}
+
+// validRole rejects roles without a name, or with the name of another role of the space.
+func (s *Server) validRole(w http.ResponseWriter, spaceID, roleID string, role client.Role) bool {
+	if role.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name is required")
+		return false
+	}
+	if slices.ContainsFunc(s.Roles[spaceID], func(other client.Role) bool { return other.Name == role.Name && other.Sys.ID != roleID }) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("a role named %s already exists", role.Name))
+		return false
+	}
+	return true
+}
+
+func (s *Server) listSpaceMemberships(w http.ResponseWriter, r *http.Request) {
+	spaceID := r.PathValue("space")
+	memberships := filter(s.SpaceMemberships, func(m client.SpaceMembership) bool { return m.Sys.Space.Sys.ID == spaceID })
+	writePage(w, r, s.PageSize, memberships)
+}
+
+// roleLinks reads role links with or without the sys wrapper, the client sends them without when creating memberships.
+type roleLinks []client.LinkSys
+
+func (l *roleLinks) UnmarshalJSON(data []byte) error {
+	var links []struct {
+		client.LinkSys
+		Sys *client.LinkSys `json:"sys"`
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		if link.Sys != nil {
+			*l = append(*l, *link.Sys)
+		} else {
+			*l = append(*l, link.LinkSys)
+		}
+	}
+	return nil
+}
+
+// createSpaceMembership adds the user or invitee with the email to the space, which requires an org membership.
+func (s *Server) createSpaceMembership(w http.ResponseWriter, r *http.Request) {
+	spaceID := r.PathValue("space")
+
+	var body struct {
+		Admin bool      `json:"admin"`
+		Email string    `json:"email"`
+		Roles roleLinks `json:"roles"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	userID := s.userIDByEmail(body.Email)
+	if userID == "" || !slices.ContainsFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool { return m.Sys.User.Sys.ID == userID }) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s is not a member of the organization", body.Email))
+		return
+	}
+	if slices.ContainsFunc(s.SpaceMemberships, func(m client.SpaceMembership) bool {
+		return m.Sys.Space.Sys.ID == spaceID && m.Sys.User.Sys.ID == userID
+	}) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s is already a member of the space", body.Email))
+		return
+	}
+
+	roles, ok := s.spaceRoleLinks(w, spaceID, body.Admin, body.Roles)
+	if !ok {
+		return
+	}
+
+	spaceMembership := client.SpaceMembership{
+		Admin: body.Admin,
+		Roles: roles,
+		Sys:   s.newSys("SpaceMembership", "space-membership"),
+	}
+	spaceMembership.Sys.Space = link("Space", spaceID)
+	spaceMembership.Sys.User = link("User", userID)
+	s.SpaceMemberships = append(s.SpaceMemberships, spaceMembership)
+	writeJSON(w, http.StatusCreated, spaceMembership)
+}
+
+func (s *Server) updateSpaceMembership(w http.ResponseWriter, r *http.Request) {
+	spaceID := r.PathValue("space")
+	i := slices.IndexFunc(s.SpaceMemberships, func(m client.SpaceMembership) bool {
+		return m.Sys.ID == r.PathValue("membership") && m.Sys.Space.Sys.ID == spaceID
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	var body struct {
+		Admin bool      `json:"admin"`
+		Roles roleLinks `json:"roles"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	roles, ok := s.spaceRoleLinks(w, spaceID, body.Admin, body.Roles)
+	if !ok || !checkVersion(w, r, &s.SpaceMemberships[i].Sys) {
+		return
+	}
+
+	s.SpaceMemberships[i].Admin = body.Admin
+	s.SpaceMemberships[i].Roles = roles
+	writeJSON(w, http.StatusOK, s.SpaceMemberships[i])
+}
+
+// spaceRoleLinks checks the roles of a space membership exist, memberships that aren't admin need at least one.
+func (s *Server) spaceRoleLinks(w http.ResponseWriter, spaceID string, admin bool, links roleLinks) ([]client.LinkRole, bool) {
+	if !admin && len(links) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "memberships that aren't admin need at least one role")
+		return nil, false
+	}
+
+	rv := make([]client.LinkRole, 0, len(links))
+	for _, l := range links {
+		if !slices.ContainsFunc(s.Roles[spaceID], func(role client.Role) bool { return role.Sys.ID == l.ID }) {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("role %s does not exist in space %s", l.ID, spaceID))
+			return nil, false
+		}
+		rv = append(rv, client.LinkRole{Sys: link("Role", l.ID).Sys})
+	}
+	return rv, true
+}
+
+func (s *Server) deleteSpaceMembership(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.SpaceMemberships, func(m client.SpaceMembership) bool {
+		return m.Sys.ID == r.PathValue("membership") && m.Sys.Space.Sys.ID == r.PathValue("space")
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	s.SpaceMemberships = slices.Delete(s.SpaceMemberships, i, i+1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listEnvironments(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.Environments[r.PathValue("space")])
+}
+
+func (s *Server) listLocales(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.Locales[r.PathValue("space")+"/"+r.PathValue("env")])
+}
+
+func (s *Server) listContentTypes(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.ContentTypes[r.PathValue("space")+"/"+r.PathValue("env")])
+}
+
+func (s *Server) listEnvironmentAliases(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.EnvironmentAliases[r.PathValue("space")])
+}
+
+func (s *Server) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.PageSize, s.APIKeys[r.PathValue("space")])
+}
+
+// userByID returns the user, or an invitee without a user with only their email.
+func (s *Server) userByID(userID string) (client.User, bool) {
+	for _, user := range s.Users {
+		if user.Sys.ID == userID {
+			return user, true
+		}
+	}
+	for email, inviteeID := range s.invitees {
+		if inviteeID == userID {
+			return client.User{Email: email, Sys: client.SystemInfo{Type: "User", ID: userID}}, true
+		}
+	}
+	return client.User{}, false
+}
+
+// userIDByEmail returns the ID of the user or invitee with the email, or "".
+func (s *Server) userIDByEmail(email string) string {
+	for _, user := range s.Users {
+		if strings.EqualFold(user.Email, email) {
+			return user.Sys.ID
+		}
+	}
+	return s.invitees[strings.ToLower(email)]
+}
+
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func (s *Server) newSys(sysType, idPrefix string) client.SystemInfo {
+	now := time.Now().UTC()
+	return client.SystemInfo{
+		Type:      sysType,
+		ID:        s.newID(idPrefix),
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func link(linkType, id string) client.Link {
+	return client.Link{
+		Sys: client.LinkSys{
+			Type:     "Link",
+			LinkType: linkType,
+			ID:       id,
+		},
+	}
+}
+
+// checkVersion rejects the update unless it carries the current version of the entity, which it bumps otherwise.
+func checkVersion(w http.ResponseWriter, r *http.Request, sys *client.SystemInfo) bool {
+	version, err := strconv.Atoi(r.Header.Get("X-Contentful-Version"))
+	if err != nil || version != sys.Version {
+		writeError(w, http.StatusConflict, fmt.Sprintf("version %q is not the current version %d", r.Header.Get("X-Contentful-Version"), sys.Version))
+		return false
+	}
+
+	sys.Version++
+	sys.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+func matches(query map[string][]string, key, value string) bool {
+	values, ok := query[key]
+	return !ok || slices.Contains(values, value)
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	rv := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep(item) {
+			rv = append(rv, item)
+		}
+	}
+	return rv
+}
+
+// readSince reads the sys.updatedAt[gte] filter, the zero time when unset.
+func readSince(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	value := r.URL.Query().Get("sys.updatedAt[gte]")
+	if value == "" {
+		return time.Time{}, true
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid sys.updatedAt[gte]: %v", err))
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// writePage writes the items of the page the limit and skip query parameters select.
+func writePage[T any](w http.ResponseWriter, r *http.Request, pageSize int, items []T) {
+	writeIncludedPage(w, r, pageSize, items, nil)
+}
+
+// writeIncludedPage adds what includes returns for the page as the page's includes, unless includes is nil.
+func writeIncludedPage[T any](w http.ResponseWriter, r *http.Request, pageSize int, items []T, includes func(page []T) any) {
+	query := r.URL.Query()
+	limit, skip := defaultLimit, 0
+	var err error
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+			return
+		}
+	}
+	if value := query.Get("skip"); value != "" {
+		skip, err = strconv.Atoi(value)
+		if err != nil || skip < 0 {
+			writeError(w, http.StatusBadRequest, "skip must be a positive number")
+			return
+		}
+	}
+	if pageSize > 0 {
+		limit = min(limit, pageSize)
+	}
+
+	start := min(skip, len(items))
+	end := min(start+limit, len(items))
+	page := struct {
+		client.Response
+		Items    []T `json:"items"`
+		Includes any `json:"includes,omitempty"`
+	}{
+		Response: client.Response{
+			Total: len(items),
+			Limit: limit,
+			Skip:  skip,
+			Sys:   client.SystemInfo{Type: "Array"},
+		},
+		Items: append([]T{}, items[start:end]...),
+	}
+	if includes != nil {
+		page.Includes = includes(page.Items)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error payload Contentful sends, which decodes to a client.ErrorResponse.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	id, ok := errorIDs[statusCode]
+	if !ok {
+		id = strings.ReplaceAll(http.StatusText(statusCode), " ", "")
+	}
+
+	writeJSON(w, statusCode, client.ErrorResponse{
+		RequestID: fmt.Sprintf("clienttest-%d", time.Now().UnixNano()),
+		Msg:       message,
+		Sys:       client.SystemInfo{Type: "Error", ID: id},
+	})
+}
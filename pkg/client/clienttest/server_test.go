@@ -0,0 +1,74 @@
+package clienttest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerPaginates(t *testing.T) {
+	s := NewServer(t, "org-1")
+	s.PageSize = 2
+	for _, id := range []string{"user-1", "user-2", "user-3"} {
+		s.Users = append(s.Users, client.User{Sys: client.SystemInfo{ID: id}})
+	}
+	c := s.Client(t)
+
+	res, err := c.ListUsers(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Total)
+	require.Len(t, res.Items, 2)
+
+	res, err = c.ListUsers(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	require.Equal(t, "user-3", res.Items[0].Sys.ID)
+
+	res, err = c.ListUsers(context.Background(), 3)
+	require.NoError(t, err)
+	require.Empty(t, res.Items)
+}
+
+func TestServerRejectsStaleVersions(t *testing.T) {
+	s := NewServer(t, "org-1")
+	s.Spaces = []client.Space{{Name: "Blog", Sys: client.SystemInfo{ID: "space-1"}}}
+	s.Roles["space-1"] = []client.Role{{Name: "Editor", Sys: client.SystemInfo{ID: "role-1", Version: 3}}}
+	c := s.Client(t)
+
+	role := client.Role{Name: "Editor", Description: "Edits entries", Sys: client.SystemInfo{ID: "role-1", Version: 2}}
+	_, err := c.UpdateSpaceRole(context.Background(), "space-1", &role)
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	require.ErrorContains(t, err, "VersionMismatch")
+
+	role.Sys.Version = 3
+	updated, err := c.UpdateSpaceRole(context.Background(), "space-1", &role)
+	require.NoError(t, err)
+	require.Equal(t, 4, updated.Sys.Version)
+	require.Equal(t, "Edits entries", updated.Description)
+
+	// the version the update was made with is stale now
+	_, err = c.UpdateSpaceRole(context.Background(), "space-1", &role)
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestServerErrors(t *testing.T) {
+	s := NewServer(t, "org-1")
+	s.Fail(http.MethodGet, "/organizations/org-1/teams", http.StatusForbidden)
+
+	_, err := s.Client(t).ListTeams(context.Background(), 0)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, "AccessDenied")
+
+	_, err = s.Client(t).GetUser(context.Background(), "user-1")
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	c, err := client.New(context.Background(), "org-1", "wrong-token", client.WithBaseURL(s.URL))
+	require.NoError(t, err)
+	_, err = c.ListSpaces(context.Background(), 0)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// https://www.contentful.com/developers/docs/references/content-management-api/#/reference/environments/environments-collection/get-all-environments-of-a-space
+func (c *Client) ListEnvironments(ctx context.Context, spaceID string, offset int) (*GetEnvironmentsResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/environments", c.baseURL, spaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+
+	var res GetEnvironmentsResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// https://www.contentful.com/developers/docs/references/content-management-api/#/reference/locales/locale-collection/get-all-locales-of-a-space
+func (c *Client) ListLocales(ctx context.Context, spaceID, environmentID string, offset int) (*GetLocalesResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/environments/%s/locales", c.baseURL, spaceID, environmentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+
+	var res GetLocalesResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// https://www.contentful.com/developers/docs/references/content-management-api/#/reference/content-types/content-type-collection/get-all-content-types-of-a-space
+func (c *Client) ListContentTypes(ctx context.Context, spaceID, environmentID string, offset int) (*GetContentTypesResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/environments/%s/content_types", c.baseURL, spaceID, environmentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+
+	var res GetContentTypesResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// https://www.contentful.com/developers/docs/references/content-management-api/#/reference/environment-aliases/environment-alias-collection/get-all-environment-aliases-of-a-space
+func (c *Client) ListEnvironmentAliases(ctx context.Context, spaceID string, offset int) (*GetEnvironmentAliasesResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/environment_aliases", c.baseURL, spaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+
+	var res GetEnvironmentAliasesResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// https://www.contentful.com/developers/docs/references/content-management-api/#/reference/api-keys/api-keys-collection/get-all-delivery-api-keys
+func (c *Client) ListAPIKeys(ctx context.Context, spaceID string, offset int) (*GetAPIKeysResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/api_keys", c.baseURL, spaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+
+	var res GetAPIKeysResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
@@ -0,0 +1,221 @@
+package client_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/conductorone/baton-contentful/pkg/client/clienttest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var record = flag.Bool("record", false, "record the fixtures in testdata from Contentful, with BATON_TOKEN and BATON_ORGANIZATION_ID")
+
+// fixtureClient returns a client replaying the fixtures of testdata/<name>.json, or recording them with -record.
+// The expectations are on the committed fixtures, recording skips them.
+func fixtureClient(t *testing.T, name string) *client.Client {
+	path := filepath.Join("testdata", name+".json")
+	orgID, token := clienttest.FixtureOrgID, "fixture-token"
+	var fixtures *clienttest.Fixtures
+	if *record {
+		orgID, token = os.Getenv("BATON_ORGANIZATION_ID"), os.Getenv("BATON_TOKEN")
+		if orgID == "" || token == "" {
+			t.Fatal("recording needs BATON_TOKEN and BATON_ORGANIZATION_ID")
+		}
+		fixtures = clienttest.Record(t, path, orgID, token)
+	} else {
+		fixtures = clienttest.Replay(t, path)
+	}
+
+	c, err := client.New(context.Background(), orgID, token, client.WithTransport(fixtures.Transport))
+	require.NoError(t, err)
+	return c
+}
+
+// fixtureSpace returns the first space of the client's organization.
+func fixtureSpace(t *testing.T, c *client.Client) string {
+	res, err := c.ListSpaces(context.Background(), 0)
+	require.NoError(t, err)
+	for _, space := range res.Items {
+		if space.Sys.Org.Sys.ID == c.OrgID() {
+			return space.Sys.ID
+		}
+	}
+	t.Fatal("the organization has no space")
+	return ""
+}
+
+func link(linkType, id string) client.Link {
+	return client.Link{Sys: client.LinkSys{Type: "Link", LinkType: linkType, ID: id}}
+}
+
+func date(year int, month time.Month, day, hour, minute, sec int) time.Time {
+	return time.Date(year, month, day, hour, minute, sec, 0, time.UTC)
+}
+
+func TestUserFixtures(t *testing.T) {
+	ctx := context.Background()
+	c := fixtureClient(t, "users")
+
+	res, err := c.ListUsers(ctx, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Items)
+	user, err := c.GetUser(ctx, res.Items[0].Sys.ID)
+	require.NoError(t, err)
+	_, err = c.GetUser(ctx, "0000000000000000000000")
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.ErrorContains(t, err, "NotFound")
+	if *record {
+		t.Skip("recorded, the expectations are on the previous fixtures")
+	}
+
+	ada := client.User{
+		FirstName:    "First name 1",
+		LastName:     "Last name 1",
+		AvatarURL:    "REDACTED",
+		Email:        "user1@example.com",
+		Activated:    true,
+		SignInCount:  128,
+		Confirmed:    true,
+		TwoFAEnabled: true,
+		Sys: client.SystemInfo{
+			Type:      "User",
+			ID:        "4Gt2lXOc1aRZm2J9lE2zTH",
+			Version:   7,
+			CreatedAt: date(2021, time.March, 15, 10, 22, 31),
+			UpdatedAt: date(2024, time.June, 1, 8, 0, 12),
+		},
+	}
+	require.Equal(t, 2, res.Total)
+	require.Equal(t, []client.User{
+		ada,
+		{
+			FirstName: "First name 2",
+			LastName:  "Last name 2",
+			AvatarURL: "REDACTED",
+			Email:     "user2@example.com",
+			Sys: client.SystemInfo{
+				Type:      "User",
+				ID:        "1xQw8Zr0HbNs2kTq6MdVoP",
+				Version:   1,
+				CreatedAt: date(2024, time.September, 2, 14, 5, 0),
+				UpdatedAt: date(2024, time.September, 2, 14, 5, 0),
+			},
+		},
+	}, res.Items)
+	require.Equal(t, &ada, user)
+}
+
+func TestRoleFixtures(t *testing.T) {
+	c := fixtureClient(t, "roles")
+	spaceID := fixtureSpace(t, c)
+
+	res, err := c.ListSpaceRoles(context.Background(), spaceID, 0)
+	require.NoError(t, err)
+	if *record {
+		t.Skip("recorded, the expectations are on the previous fixtures")
+	}
+
+	entries := map[string]any{"equals": []any{map[string]any{"doc": "sys.type"}, "Entry"}}
+	space := link("Space", "5x1kqhb9p0xz")
+	require.Equal(t, []client.Role{
+		{
+			Name:        "Editor",
+			Description: "Allows editing, publishing and archiving of content",
+			Policies: []client.Policy{
+				{Effect: "allow", Actions: "all", Constraint: map[string]any{"and": []any{entries}}},
+			},
+			Permissions: client.Permissions{
+				ContentModel:       []any{"read"},
+				Settings:           []any{},
+				ContentDelivery:    "all",
+				Environments:       "all",
+				EnvironmentAliases: []any{},
+			},
+			Sys: client.SystemInfo{
+				Type:      "Role",
+				ID:        "6mXqE3vYpMWPb1lW8nHk2C",
+				Version:   3,
+				CreatedAt: date(2021, time.March, 15, 10, 30, 5),
+				UpdatedAt: date(2023, time.July, 19, 8, 14, 27),
+				Space:     space,
+			},
+		},
+		{
+			Name: "Translator DE",
+			Policies: []client.Policy{
+				{
+					Effect:  "allow",
+					Actions: []any{"read", "update"},
+					Constraint: map[string]any{"and": []any{
+						entries,
+						map[string]any{"paths": []any{map[string]any{"doc": "fields.%.de-DE"}}},
+					}},
+				},
+				{Effect: "deny", Actions: []any{"delete"}, Constraint: entries},
+			},
+			Permissions: client.Permissions{
+				ContentModel:    []any{"read"},
+				Settings:        []any{},
+				ContentDelivery: []any{},
+			},
+			Sys: client.SystemInfo{
+				Type:      "Role",
+				ID:        "2hT9sKc0LqVr5nYw1JxZ8a",
+				Version:   1,
+				CreatedAt: date(2023, time.January, 9, 11, 0, 0),
+				UpdatedAt: date(2023, time.January, 9, 11, 0, 0),
+				Space:     space,
+			},
+		},
+	}, res.Items)
+}
+
+func TestSpaceMembershipFixtures(t *testing.T) {
+	c := fixtureClient(t, "space_memberships")
+	spaceID := fixtureSpace(t, c)
+
+	res, err := c.ListSpaceMembers(context.Background(), spaceID, 0)
+	require.NoError(t, err)
+	if *record {
+		t.Skip("recorded, the expectations are on the previous fixtures")
+	}
+
+	space := link("Space", "5x1kqhb9p0xz")
+	require.Equal(t, []client.SpaceMembership{
+		{
+			Admin: true,
+			Roles: []client.LinkRole{},
+			Sys: client.SystemInfo{
+				Type:      "SpaceMember",
+				ID:        "0Ma1nGS7F4WSiNbNCvpCqv",
+				Version:   1,
+				CreatedAt: date(2021, time.March, 15, 10, 30, 2),
+				UpdatedAt: date(2021, time.March, 15, 10, 30, 2),
+				Space:     space,
+				User:      link("User", "4Gt2lXOc1aRZm2J9lE2zTH"),
+			},
+		},
+		{
+			Roles: []client.LinkRole{
+				{Sys: client.LinkSys{Type: "Link", LinkType: "Role", ID: "6mXqE3vYpMWPb1lW8nHk2C"}},
+				{Sys: client.LinkSys{Type: "Link", LinkType: "Role", ID: "2hT9sKc0LqVr5nYw1JxZ8a"}},
+			},
+			Sys: client.SystemInfo{
+				Type:      "SpaceMember",
+				ID:        "3pLk9dWq2ZxR7cVb0NmYt4",
+				Version:   2,
+				CreatedAt: date(2024, time.September, 2, 14, 5, 0),
+				UpdatedAt: date(2024, time.October, 7, 9, 31, 18),
+				Space:     space,
+				User:      link("User", "1xQw8Zr0HbNs2kTq6MdVoP"),
+			},
+		},
+	}, res.Items)
+}
@@ -55,9 +55,9 @@ type SystemInfo struct {
 	User      Link      `json:"user"`
 
 	// these are only for org memberships
-	LastActiveAt *time.Time  `json:"lastActiveAt"`
-	Status       string      `json:"status"`
-	SSO          interface{} `json:"sso"`
+	LastActiveAt *time.Time `json:"lastActiveAt"`
+	Status       string     `json:"status"`
+	SSO          *SSOInfo   `json:"sso"`
 
 	// only for team memberships endpoint calls
 	// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/team-memberships
@@ -67,6 +67,12 @@ type SystemInfo struct {
 	InvitationURL string `json:"invitationUrl"`
 }
 
+// SSOInfo is only set on org memberships of users that have signed in through the
+// organization's identity provider at least once.
+type SSOInfo struct {
+	LastSignInAt *time.Time `json:"lastSignInAt"`
+}
+
 type Link struct {
 	Sys LinkSys `json:"sys"`
 }
@@ -97,6 +103,18 @@ type Organization struct {
 	Sys  SystemInfo `json:"sys"`
 }
 
+// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/identity-provider
+type IdentityProvider struct {
+	SSOName              string     `json:"ssoName"`
+	IdpName              string     `json:"idpName"`
+	IdpSSOTargetURL      string     `json:"idpSsoTargetUrl"`
+	Enabled              bool       `json:"enabled"`
+	RestrictedMode       bool       `json:"restrictedMode"`
+	TestConnectionAt     *time.Time `json:"testConnectionAt"`
+	TestConnectionResult string     `json:"testConnectionResult"`
+	Sys                  SystemInfo `json:"sys"`
+}
+
 type GetTeamsResponse struct {
 	Response
 	Items []Team `json:"items"`
@@ -128,14 +146,21 @@ type Policy struct {
 }
 
 type Permissions struct {
-	ContentModel    []string `json:"ContentModel"`
-	Settings        []string `json:"Settings"`
-	ContentDelivery any      `json:"ContentDelivery"` // Can be string "all" or []string
+	ContentModel    any `json:"ContentModel"`    // Can be string "all" or []string
+	Settings        any `json:"Settings"`        // Can be string "all" or []string
+	ContentDelivery any `json:"ContentDelivery"` // Can be string "all" or []string
+	// Can be string "all" or []string, unset on spaces without environment permissions
+	Environments       any `json:"Environments,omitempty"`
+	EnvironmentAliases any `json:"EnvironmentAliases,omitempty"`
 }
 
 type GetOrganizationMembershipsResponse struct {
 	Response
 	Items []OrganizationMembership `json:"items"`
+	// only listed when the users are included
+	Includes struct {
+		User []User `json:"User"`
+	} `json:"includes"`
 }
 
 type OrganizationMembership struct {
@@ -184,3 +209,109 @@ type CreateInvitationBody struct {
 type Invitation struct {
 	Sys SystemInfo `json:"sys"`
 }
+
+type GetEnvironmentsResponse struct {
+	Response
+	Items []Environment `json:"items"`
+}
+
+type Environment struct {
+	Name string                `json:"name"`
+	Sys  EnvironmentSystemInfo `json:"sys"`
+}
+
+// EnvironmentSystemInfo is the sys of environments, their status is a link rather than a string.
+type EnvironmentSystemInfo struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Space     Link      `json:"space"`
+	// ready, queued or failed
+	Status Link `json:"status"`
+	// the aliases targeting the environment
+	Aliases []Link `json:"aliases"`
+}
+
+type GetLocalesResponse struct {
+	Response
+	Items []Locale `json:"items"`
+}
+
+type Locale struct {
+	Name                 string     `json:"name"`
+	Code                 string     `json:"code"`
+	FallbackCode         string     `json:"fallbackCode"`
+	Default              bool       `json:"default"`
+	Optional             bool       `json:"optional"`
+	ContentManagementAPI bool       `json:"contentManagementApi"`
+	ContentDeliveryAPI   bool       `json:"contentDeliveryApi"`
+	Sys                  SystemInfo `json:"sys"`
+}
+
+type GetContentTypesResponse struct {
+	Response
+	Items []ContentType `json:"items"`
+}
+
+type ContentType struct {
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	DisplayField string             `json:"displayField"`
+	Fields       []ContentTypeField `json:"fields"`
+	Sys          SystemInfo         `json:"sys"`
+}
+
+type ContentTypeField struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Localized bool   `json:"localized"`
+	Required  bool   `json:"required"`
+	Disabled  bool   `json:"disabled"`
+	Omitted   bool   `json:"omitted"`
+}
+
+type GetEnvironmentAliasesResponse struct {
+	Response
+	Items []EnvironmentAlias `json:"items"`
+}
+
+type EnvironmentAlias struct {
+	// the environment the alias targets
+	Environment Link       `json:"environment"`
+	Sys         SystemInfo `json:"sys"`
+}
+
+type GetAPIKeysResponse struct {
+	Response
+	Items []APIKey `json:"items"`
+}
+
+// APIKey is a Content Delivery API key, the environments and aliases it delivers content of are linked.
+type APIKey struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Environments []Link     `json:"environments"`
+	Sys          SystemInfo `json:"sys"`
+}
+
+type GetPeriodicUsagesResponse struct {
+	Response
+	Items []PeriodicUsage `json:"items"`
+}
+
+// PeriodicUsage is the usage of an API over a date range, the sys of space usages links their space.
+type PeriodicUsage struct {
+	Metric        string     `json:"metric"`
+	Usage         int64      `json:"usage"`
+	UnitOfMeasure string     `json:"unitOfMeasure"`
+	DateRange     DateRange  `json:"dateRange"`
+	Sys           SystemInfo `json:"sys"`
+}
+
+type DateRange struct {
+	StartAt string `json:"startAt"`
+	EndAt   string `json:"endAt"`
+}
@@ -1,15 +1,19 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
 func (c *Client) ListOrganizations(ctx context.Context, offset int) (*GetOrganizationsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations", BaseURL), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations", c.baseURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -35,15 +39,62 @@ func (c *Client) ListOrganizations(ctx context.Context, offset int) (*GetOrganiz
 
 // https://www.contentful.com/developers/docs/references/user-management-api/#/reference/organization-memberships
 func (c *Client) ListOrganizationMemberships(ctx context.Context, offset int) (*GetOrganizationMembershipsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/organization_memberships", BaseURL, c.orgID), nil)
+	return c.listOrganizationMemberships(ctx, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+}
+
+// ListOrganizationMembershipsUpdatedSince only lists the org memberships updated at or after since.
+func (c *Client) ListOrganizationMembershipsUpdatedSince(ctx context.Context, since time.Time, offset int) (*GetOrganizationMembershipsResponse, error) {
+	return c.listOrganizationMemberships(ctx, map[string]string{
+		"limit":              fmt.Sprintf("%d", defaultLimit),
+		"skip":               fmt.Sprintf("%d", offset),
+		"sys.updatedAt[gte]": since.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// ListOrganizationMembershipsWithUsers lists the org memberships along with their users, pending invitees included.
+func (c *Client) ListOrganizationMembershipsWithUsers(ctx context.Context, offset int) (*GetOrganizationMembershipsResponse, error) {
+	return c.listOrganizationMemberships(ctx, map[string]string{
+		"limit":   fmt.Sprintf("%d", defaultLimit),
+		"skip":    fmt.Sprintf("%d", offset),
+		"include": "sys.user",
+	})
+}
+
+// CountOrganizationMemberships returns the number of memberships of the organization.
+func (c *Client) CountOrganizationMemberships(ctx context.Context) (int, error) {
+	res, err := c.listOrganizationMemberships(ctx, map[string]string{
+		"limit": "1",
+	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	SetQueryParams(req.URL, map[string]string{
-		"limit": fmt.Sprintf("%d", defaultLimit),
-		"skip":  fmt.Sprintf("%d", offset),
+	return res.Total, nil
+}
+
+// CountOrganizationMembershipsByRole returns the number of memberships of the organization with the role.
+func (c *Client) CountOrganizationMembershipsByRole(ctx context.Context, role string) (int, error) {
+	res, err := c.listOrganizationMemberships(ctx, map[string]string{
+		"role":  role,
+		"limit": "1",
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.Total, nil
+}
+
+func (c *Client) listOrganizationMemberships(ctx context.Context, params map[string]string) (*GetOrganizationMembershipsResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/organization_memberships", c.baseURL, c.orgID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, params)
 
 	var res GetOrganizationMembershipsResponse
 	resp, err := c.Do(req,
@@ -60,7 +111,7 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, offset int) (*
 }
 
 func (c *Client) GetOrganizationMembershipByUser(ctx context.Context, userID string) (*GetOrganizationMembershipsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/organization_memberships", BaseURL, c.orgID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/organization_memberships", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +135,7 @@ func (c *Client) GetOrganizationMembershipByUser(ctx context.Context, userID str
 }
 
 func (c *Client) DeleteOrganizationMembership(ctx context.Context, orgMembershipID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/organizations/%s/organization_memberships/%s", BaseURL, c.orgID, orgMembershipID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/organizations/%s/organization_memberships/%s", c.baseURL, c.orgID, orgMembershipID), nil)
 	if err != nil {
 		return err
 	}
@@ -100,3 +151,57 @@ func (c *Client) DeleteOrganizationMembership(ctx context.Context, orgMembership
 
 	return nil
 }
+
+// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/identity-provider
+func (c *Client) GetIdentityProvider(ctx context.Context, orgID string) (*IdentityProvider, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/identity_provider", c.baseURL, orgID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res IdentityProvider
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// UpdateOrganizationMembership saves the role and restricted mode exemption of the org membership.
+// The membership's sys.version must be the current one, Contentful rejects the update otherwise.
+func (c *Client) UpdateOrganizationMembership(ctx context.Context, orgMembership *OrganizationMembership) (*OrganizationMembership, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"role":                       orgMembership.Role,
+		"isExemptFromRestrictedMode": orgMembership.IsExemptFromRestrictedMode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/organizations/%s/organization_memberships/%s", c.baseURL, c.orgID, orgMembership.Sys.ID), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set("X-Contentful-Version", strconv.Itoa(orgMembership.Sys.Version))
+
+	var res OrganizationMembership
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
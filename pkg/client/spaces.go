@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
 func (c *Client) ListSpaces(ctx context.Context, offset int) (*GetSpacesResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces", BaseURL), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces", c.baseURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +40,7 @@ func (c *Client) ListSpaces(ctx context.Context, offset int) (*GetSpacesResponse
 // https://www.contentful.com/developers/docs/references/content-management-api/#/reference/roles/roles-collection/get-all-roles/console/curl
 // https://www.contentful.com/help/roles/space-roles-and-permissions/
 func (c *Client) ListSpaceRoles(ctx context.Context, spaceID string, offset int) (*GetSpaceRolesResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/roles", BaseURL, spaceID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/roles", c.baseURL, spaceID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -62,8 +64,86 @@ func (c *Client) ListSpaceRoles(ctx context.Context, spaceID string, offset int)
 	return &res, nil
 }
 
+// CreateSpace creates a space in the client's organization.
+func (c *Client) CreateSpace(ctx context.Context, name, defaultLocale string) (*Space, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"name":          name,
+		"defaultLocale": defaultLocale,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/spaces", c.baseURL), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set("X-Contentful-Organization", c.orgID)
+
+	var res Space
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// CreateSpaceRole creates a role in the space with the name, description, policies and permissions of role.
+func (c *Client) CreateSpaceRole(ctx context.Context, spaceID string, role *Role) (*Role, error) {
+	return c.saveSpaceRole(ctx, http.MethodPost, fmt.Sprintf("%s/spaces/%s/roles", c.baseURL, spaceID), role)
+}
+
+// UpdateSpaceRole saves the name, description, policies and permissions of the role.
+// The role's sys.version must be the current one, Contentful rejects the update otherwise.
+func (c *Client) UpdateSpaceRole(ctx context.Context, spaceID string, role *Role) (*Role, error) {
+	return c.saveSpaceRole(ctx, http.MethodPut, fmt.Sprintf("%s/spaces/%s/roles/%s", c.baseURL, spaceID, role.Sys.ID), role)
+}
+
+func (c *Client) saveSpaceRole(ctx context.Context, method, url string, role *Role) (*Role, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"name":        role.Name,
+		"description": role.Description,
+		"policies":    role.Policies,
+		"permissions": role.Permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	if method == http.MethodPut {
+		req.Header.Set("X-Contentful-Version", strconv.Itoa(role.Sys.Version))
+	}
+
+	var res Role
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
 func (c *Client) ListSpaceMembers(ctx context.Context, spaceID string, offset int) (*GetSpaceMembershipsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/space_members", BaseURL, spaceID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/spaces/%s/space_members", c.baseURL, spaceID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +167,58 @@ func (c *Client) ListSpaceMembers(ctx context.Context, spaceID string, offset in
 	return &res, nil
 }
 
+// ListOrganizationSpaceMemberships lists the space memberships of every space in the organization.
+// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/space-memberships
+func (c *Client) ListOrganizationSpaceMemberships(ctx context.Context, offset int) (*GetSpaceMembershipsResponse, error) {
+	return c.listOrganizationSpaceMemberships(ctx, map[string]string{
+		"limit": fmt.Sprintf("%d", defaultLimit),
+		"skip":  fmt.Sprintf("%d", offset),
+	})
+}
+
+// ListOrganizationSpaceMembershipsUpdatedSince only lists the space memberships updated at or after since.
+func (c *Client) ListOrganizationSpaceMembershipsUpdatedSince(ctx context.Context, since time.Time, offset int) (*GetSpaceMembershipsResponse, error) {
+	return c.listOrganizationSpaceMemberships(ctx, map[string]string{
+		"limit":              fmt.Sprintf("%d", defaultLimit),
+		"skip":               fmt.Sprintf("%d", offset),
+		"sys.updatedAt[gte]": since.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// CountOrganizationSpaceMemberships returns the number of space memberships in the organization.
+func (c *Client) CountOrganizationSpaceMemberships(ctx context.Context) (int, error) {
+	res, err := c.listOrganizationSpaceMemberships(ctx, map[string]string{
+		"limit": "1",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.Total, nil
+}
+
+func (c *Client) listOrganizationSpaceMemberships(ctx context.Context, params map[string]string) (*GetSpaceMembershipsResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/space_memberships", c.baseURL, c.orgID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetQueryParams(req.URL, params)
+
+	var res GetSpaceMembershipsResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
 func (c *Client) CreateSpaceMembership(ctx context.Context, spaceID, email string, roleID string, isAdmin bool) (*SpaceMembership, error) {
 	body := map[string]interface{}{
 		"admin": isAdmin,
@@ -108,12 +240,57 @@ func (c *Client) CreateSpaceMembership(ctx context.Context, spaceID, email strin
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/spaces/%s/space_memberships", BaseURL, spaceID), bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/spaces/%s/space_memberships", c.baseURL, spaceID), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+
+	var res SpaceMembership
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+// UpdateSpaceMembership saves whether the space membership is an admin one and its roles.
+// The membership's sys.version must be the current one, Contentful rejects the update otherwise.
+func (c *Client) UpdateSpaceMembership(ctx context.Context, spaceMembership *SpaceMembership) (*SpaceMembership, error) {
+	roles := make([]Link, 0, len(spaceMembership.Roles))
+	for _, role := range spaceMembership.Roles {
+		roles = append(roles, Link{
+			Sys: LinkSys{
+				Type:     "Link",
+				LinkType: "Role",
+				ID:       role.Sys.ID,
+			},
+		})
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"admin": spaceMembership.Admin,
+		"roles": roles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	spaceID := spaceMembership.Sys.Space.Sys.ID
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/spaces/%s/space_memberships/%s", c.baseURL, spaceID, spaceMembership.Sys.ID), bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set("X-Contentful-Version", strconv.Itoa(spaceMembership.Sys.Version))
 
 	var res SpaceMembership
 	resp, err := c.Do(req,
@@ -130,7 +307,7 @@ func (c *Client) CreateSpaceMembership(ctx context.Context, spaceID, email strin
 }
 
 func (c *Client) DeleteSpaceMembership(ctx context.Context, spaceID, spaceMembershipID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/spaces/%s/space_memberships/%s", BaseURL, spaceID, spaceMembershipID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/spaces/%s/space_memberships/%s", c.baseURL, spaceID, spaceMembershipID), nil)
 	if err != nil {
 		return err
 	}
@@ -148,7 +325,7 @@ func (c *Client) DeleteSpaceMembership(ctx context.Context, spaceID, spaceMember
 }
 
 func (c *Client) GetSpaceMembershipByUser(ctx context.Context, spaceID, userID string) (*GetSpaceMembershipsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/space_memberships", BaseURL, c.orgID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/space_memberships", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
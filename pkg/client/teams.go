@@ -11,7 +11,7 @@ import (
 )
 
 func (c *Client) ListTeams(ctx context.Context, offset int) (*GetTeamsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/teams", BaseURL, c.orgID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/teams", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -35,8 +35,57 @@ func (c *Client) ListTeams(ctx context.Context, offset int) (*GetTeamsResponse,
 	return &res, nil
 }
 
+func (c *Client) CreateTeam(ctx context.Context, name, description string) (*Team, error) {
+	body := map[string]interface{}{
+		"name":        name,
+		"description": description,
+	}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/organizations/%s/teams", c.baseURL, c.orgID), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+
+	var res Team
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
+
+func (c *Client) DeleteTeam(ctx context.Context, teamID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/organizations/%s/teams/%s", c.baseURL, c.orgID, teamID), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req,
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c *Client) ListTeamMemberships(ctx context.Context, offset int) (*GetTeamMembershipsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/team_memberships", BaseURL, c.orgID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/team_memberships", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +118,7 @@ func (c *Client) CreateTeamMembership(ctx context.Context, teamID string, orgMem
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/organizations/%s/teams/%s/team_memberships", BaseURL, c.orgID, teamID), bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/organizations/%s/teams/%s/team_memberships", c.baseURL, c.orgID, teamID), bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +140,7 @@ func (c *Client) CreateTeamMembership(ctx context.Context, teamID string, orgMem
 }
 
 func (c *Client) GetTeamMembershipByUser(ctx context.Context, orgMembershipID string) (*GetTeamMembershipsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/team_memberships", BaseURL, c.orgID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/team_memberships", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +164,7 @@ func (c *Client) GetTeamMembershipByUser(ctx context.Context, orgMembershipID st
 }
 
 func (c *Client) DeleteTeamMembership(ctx context.Context, teamID, teamMembershipID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/organizations/%s/teams/%s/team_memberships/%s", BaseURL, c.orgID, teamID, teamMembershipID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/organizations/%s/teams/%s/team_memberships/%s", c.baseURL, c.orgID, teamID, teamMembershipID), nil)
 	if err != nil {
 		return err
 	}
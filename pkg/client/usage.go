@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// usageMetrics are the APIs usage is reported for: management, delivery, preview and GraphQL.
+const usageMetrics = "cma,cda,cpa,gql"
+
+// ListOrganizationPeriodicUsages returns the API usage of the organization in the current billing period, one per metric.
+// The usage endpoints are an alpha feature, not every organization can read them.
+// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/usage/organization-usage
+func (c *Client) ListOrganizationPeriodicUsages(ctx context.Context) (*GetPeriodicUsagesResponse, error) {
+	return c.listPeriodicUsages(ctx, fmt.Sprintf("%s/organizations/%s/organization_periodic_usages", c.baseURL, c.orgID), 0)
+}
+
+// ListSpacePeriodicUsages returns the API usage of each space of the organization in the current billing period,
+// one per space and metric.
+// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/usage/space-usage
+func (c *Client) ListSpacePeriodicUsages(ctx context.Context, offset int) (*GetPeriodicUsagesResponse, error) {
+	return c.listPeriodicUsages(ctx, fmt.Sprintf("%s/organizations/%s/space_periodic_usages", c.baseURL, c.orgID), offset)
+}
+
+func (c *Client) listPeriodicUsages(ctx context.Context, url string, offset int) (*GetPeriodicUsagesResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Contentful-Enable-Alpha-Feature", "usage-insights")
+	SetQueryParams(req.URL, map[string]string{
+		"metric[in]": usageMetrics,
+		"limit":      fmt.Sprintf("%d", defaultLimit),
+		"skip":       fmt.Sprintf("%d", offset),
+	})
+
+	var res GetPeriodicUsagesResponse
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return &res, nil
+}
@@ -6,13 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
 func (c *Client) ListUsers(ctx context.Context, offset int) (*GetUsersResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/users", BaseURL, c.orgID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/users", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -36,14 +35,15 @@ func (c *Client) ListUsers(ctx context.Context, offset int) (*GetUsersResponse,
 	return &res, nil
 }
 
-func (c *Client) GetUserByID(ctx context.Context, userID string) (*GetUsersResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/users", BaseURL, c.orgID), nil)
+// SearchUsers lists the users of the organization whose ID, email or names match the query.
+func (c *Client) SearchUsers(ctx context.Context, query string) (*GetUsersResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/users", c.baseURL, c.orgID), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	SetQueryParams(req.URL, map[string]string{
-		"query": userID,
+		"query": query,
 	})
 
 	var res GetUsersResponse
@@ -60,18 +60,14 @@ func (c *Client) GetUserByID(ctx context.Context, userID string) (*GetUsersRespo
 	return &res, nil
 }
 
-func (c *Client) CreateInvitation(ctx context.Context, body *CreateInvitationBody) (*Invitation, error) {
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/organizations/%s/invitations", BaseURL, c.orgID), bytes.NewReader(bodyBytes))
+// https://www.contentful.com/developers/docs/references/user-management-api/#/reference/users/user/get-a-single-user
+func (c *Client) GetUser(ctx context.Context, userID string) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/organizations/%s/users/%s", c.baseURL, c.orgID, userID), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
 
-	var res Invitation
+	var res User
 	resp, err := c.Do(req,
 		uhttp.WithJSONResponse(&res),
 		uhttp.WithErrorResponse(&ErrorResponse{}),
@@ -85,15 +81,27 @@ func (c *Client) CreateInvitation(ctx context.Context, body *CreateInvitationBod
 	return &res, nil
 }
 
-func (c *Client) GetLastActiveAt(ctx context.Context, userID string) *time.Time {
-	res, err := c.GetOrganizationMembershipByUser(ctx, userID)
+func (c *Client) CreateInvitation(ctx context.Context, body *CreateInvitationBody) (*Invitation, error) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/organizations/%s/invitations", c.baseURL, c.orgID), bytes.NewReader(bodyBytes))
 	if err != nil {
-		return nil
+		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
 
-	if len(res.Items) == 0 {
-		return nil
+	var res Invitation
+	resp, err := c.Do(req,
+		uhttp.WithJSONResponse(&res),
+		uhttp.WithErrorResponse(&ErrorResponse{}),
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return res.Items[0].Sys.LastActiveAt
+	defer resp.Body.Close()
+
+	return &res, nil
 }
@@ -0,0 +1,421 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	actionResendInvitation       = "resend_invitation"
+	actionTransferSpaceOwnership = "transfer_space_ownership"
+	actionSetRestrictedExemption = "set_restricted_mode_exemption"
+	actionCloneSpaceRoles        = "clone_space_roles"
+)
+
+// action is a custom action and the function running it once its arguments are validated.
+type action struct {
+	schema *v2.BatonActionSchema
+	invoke func(ctx context.Context, args map[string]*structpb.Value) (*structpb.Struct, error)
+}
+
+func stringArgument(name, displayName, description string, required bool) *config.Field {
+	return &config.Field{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		IsRequired:  required,
+		Field: &config.Field_StringField{
+			StringField: &config.StringField{},
+		},
+	}
+}
+
+func boolArgument(name, displayName, description string, required bool) *config.Field {
+	return &config.Field{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		IsRequired:  required,
+		Field: &config.Field_BoolField{
+			BoolField: &config.BoolField{},
+		},
+	}
+}
+
+func stringListArgument(name, displayName, description string, required bool) *config.Field {
+	return &config.Field{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		IsRequired:  required,
+		Field: &config.Field_StringSliceField{
+			StringSliceField: &config.StringSliceField{},
+		},
+	}
+}
+
+func (d *Connector) actions() map[string]action {
+	return map[string]action{
+		actionResendInvitation: {
+			schema: &v2.BatonActionSchema{
+				Name:        actionResendInvitation,
+				DisplayName: "Resend invitation",
+				Description: "Invite the email to the organization again, Contentful sends a new invitation email.",
+				Arguments: []*config.Field{
+					stringArgument("email", "Email", "The email address the invitation was sent to.", true),
+					stringArgument("role", "Role", fmt.Sprintf("Organization role, one of: %s. Defaults to the role of the pending invitation, or '%s'.", strings.Join(orgRoles, ", "), orgMember), false),
+				},
+				ReturnTypes: []*config.Field{
+					stringArgument("invitation_id", "Invitation ID", "The ID of the new invitation.", true),
+					stringArgument("invitation_url", "Invitation URL", "The URL the invitee accepts the invitation at.", true),
+				},
+			},
+			invoke: d.resendInvitation,
+		},
+		actionTransferSpaceOwnership: {
+			schema: &v2.BatonActionSchema{
+				Name:        actionTransferSpaceOwnership,
+				DisplayName: "Transfer space ownership",
+				Description: "Make a user admin of the space in place of its current admin.",
+				Arguments: []*config.Field{
+					stringArgument("space_id", "Space ID", "The space to transfer.", true),
+					stringArgument("from_user_id", "Current admin", "The user ID of the current space admin.", true),
+					stringArgument("to_user_id", "New admin", "The user ID of the new space admin.", true),
+					stringArgument("previous_admin_role", "Role of the current admin", "Name of the space role the current admin keeps. Their space membership is removed when empty.", false),
+				},
+				ReturnTypes: []*config.Field{
+					boolArgument("success", "Success", "Whether the space was transferred.", true),
+				},
+			},
+			invoke: d.transferSpaceOwnership,
+		},
+		actionSetRestrictedExemption: {
+			schema: &v2.BatonActionSchema{
+				Name:        actionSetRestrictedExemption,
+				DisplayName: "Set SSO restricted mode exemption",
+				Description: "Allow or stop a user signing in without SSO while the organization is in SSO restricted mode.",
+				Arguments: []*config.Field{
+					stringArgument("user_id", "User ID", "The user to update.", true),
+					boolArgument("exempt", "Exempt", "Whether the user is exempt from restricted mode.", true),
+				},
+				ReturnTypes: []*config.Field{
+					boolArgument("success", "Success", "Whether the org membership was updated.", true),
+				},
+			},
+			invoke: d.setRestrictedModeExemption,
+		},
+		actionCloneSpaceRoles: {
+			schema: &v2.BatonActionSchema{
+				Name:        actionCloneSpaceRoles,
+				DisplayName: "Clone space roles",
+				Description: "Copy the roles of a template space into another space, matched by name. Roles only in the target space are left alone.",
+				Arguments: []*config.Field{
+					stringArgument("template_space_id", "Template space ID", "The space the roles are copied from.", true),
+					stringArgument("target_space_id", "Target space ID", "The space the roles are copied to.", true),
+					boolArgument("dry_run", "Dry run", "Only report what would change.", false),
+				},
+				ReturnTypes: []*config.Field{
+					boolArgument("dry_run", "Dry run", "Whether nothing was written.", true),
+					stringListArgument("created", "Created", "Roles missing from the target space.", true),
+					stringListArgument("updated", "Updated", "Roles whose description, policies or permissions differ.", true),
+					stringListArgument("unchanged", "Unchanged", "Roles already the same as the template's.", true),
+					stringListArgument("diff", "Diff", "What changes for each template role.", true),
+				},
+			},
+			invoke: d.cloneSpaceRoles,
+		},
+	}
+}
+
+func (d *Connector) ListActionSchemas(ctx context.Context) ([]*v2.BatonActionSchema, annotations.Annotations, error) {
+	actions := d.actions()
+	rv := make([]*v2.BatonActionSchema, 0, len(actions))
+	for _, a := range actions {
+		rv = append(rv, a.schema)
+	}
+	slices.SortFunc(rv, func(a, b *v2.BatonActionSchema) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return rv, nil, nil
+}
+
+func (d *Connector) GetActionSchema(ctx context.Context, name string) (*v2.BatonActionSchema, annotations.Annotations, error) {
+	a, ok := d.actions()[name]
+	if !ok {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-contentful: unknown action %s", name)
+	}
+
+	return a.schema, nil, nil
+}
+
+// InvokeAction runs the action to completion, none of them take long enough to need polling.
+func (d *Connector) InvokeAction(ctx context.Context, name string, args *structpb.Struct) (string, v2.BatonActionStatus, *structpb.Struct, annotations.Annotations, error) {
+	a, ok := d.actions()[name]
+	if !ok {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, status.Errorf(codes.NotFound, "baton-contentful: unknown action %s", name)
+	}
+
+	fields := args.GetFields()
+	err := validateActionArgs(a.schema, fields)
+	if err != nil {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+	}
+
+	rv, err := a.invoke(ctx, fields)
+	if err != nil {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+	}
+
+	return "", v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, rv, nil, nil
+}
+
+// GetActionStatus has nothing to report, actions complete before InvokeAction returns.
+func (d *Connector) GetActionStatus(ctx context.Context, id string) (v2.BatonActionStatus, string, *structpb.Struct, annotations.Annotations, error) {
+	return v2.BatonActionStatus_BATON_ACTION_STATUS_UNKNOWN, "", nil, nil, status.Errorf(codes.NotFound, "baton-contentful: no pending action %s", id)
+}
+
+// validateActionArgs checks every argument is declared by the schema with a value of its type,
+// and every required argument is set.
+func validateActionArgs(schema *v2.BatonActionSchema, args map[string]*structpb.Value) error {
+	declared := make(map[string]*config.Field, len(schema.Arguments))
+	for _, field := range schema.Arguments {
+		declared[field.Name] = field
+	}
+
+	for name, value := range args {
+		field, ok := declared[name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "baton-contentful: unknown argument %s for action %s", name, schema.Name)
+		}
+
+		switch field.Field.(type) {
+		case *config.Field_StringField:
+			if _, ok := value.GetKind().(*structpb.Value_StringValue); !ok {
+				return status.Errorf(codes.InvalidArgument, "baton-contentful: argument %s must be a string", name)
+			}
+		case *config.Field_BoolField:
+			if _, ok := value.GetKind().(*structpb.Value_BoolValue); !ok {
+				return status.Errorf(codes.InvalidArgument, "baton-contentful: argument %s must be a boolean", name)
+			}
+		}
+	}
+
+	for _, field := range schema.Arguments {
+		if !field.IsRequired {
+			continue
+		}
+		value, ok := args[field.Name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "baton-contentful: argument %s is required", field.Name)
+		}
+		if s, isString := value.GetKind().(*structpb.Value_StringValue); isString && strings.TrimSpace(s.StringValue) == "" {
+			return status.Errorf(codes.InvalidArgument, "baton-contentful: argument %s is required", field.Name)
+		}
+	}
+
+	return nil
+}
+
+func successResult() *structpb.Struct {
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"success": structpb.NewBoolValue(true),
+		},
+	}
+}
+
+func (d *Connector) resendInvitation(ctx context.Context, args map[string]*structpb.Value) (*structpb.Struct, error) {
+	email := strings.TrimSpace(args["email"].GetStringValue())
+	role := strings.TrimSpace(args["role"].GetStringValue())
+	if role == "" {
+		var err error
+		role, err = d.pendingInvitationRole(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if role == "" {
+		role = orgMember
+	}
+	if !slices.Contains(orgRoles, role) {
+		return nil, status.Errorf(codes.InvalidArgument, "baton-contentful: invalid role '%s', must be one of: %s", role, strings.Join(orgRoles, ", "))
+	}
+
+	invitation, err := d.client.CreateInvitation(ctx, &client.CreateInvitationBody{
+		Email: email,
+		Role:  role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to resend invitation: %w", err)
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"invitation_id":  structpb.NewStringValue(invitation.Sys.ID),
+			"invitation_url": structpb.NewStringValue(invitation.Sys.InvitationURL),
+		},
+	}, nil
+}
+
+// pendingInvitationRole returns the role of the email's pending org membership, or "" when the email has none.
+func (d *Connector) pendingInvitationRole(ctx context.Context, email string) (string, error) {
+	var offset int
+	for {
+		res, err := d.client.ListOrganizationMembershipsWithUsers(ctx, offset)
+		if err != nil {
+			return "", fmt.Errorf("baton-contentful: failed to list org memberships: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			return "", nil
+		}
+
+		invitees := make(map[string]bool)
+		for _, user := range res.Includes.User {
+			if strings.EqualFold(user.Email, email) {
+				invitees[user.Sys.ID] = true
+			}
+		}
+		for _, orgMembership := range res.Items {
+			if orgMembership.Sys.Status == orgMembershipPending && invitees[orgMembership.Sys.User.Sys.ID] {
+				return orgMembership.Role, nil
+			}
+		}
+
+		offset += len(res.Items)
+	}
+}
+
+// getSpaceMembership returns nil when the user isn't a member of the space.
+func (d *Connector) getSpaceMembership(ctx context.Context, spaceID, userID string) (*client.SpaceMembership, error) {
+	res, err := d.client.GetSpaceMembershipByUser(ctx, spaceID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to get space membership of user %s: %w", userID, err)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, nil
+	}
+
+	return &res.Items[0], nil
+}
+
+func (d *Connector) transferSpaceOwnership(ctx context.Context, args map[string]*structpb.Value) (*structpb.Struct, error) {
+	spaceID := args["space_id"].GetStringValue()
+	fromUserID := args["from_user_id"].GetStringValue()
+	toUserID := args["to_user_id"].GetStringValue()
+	previousAdminRole := strings.TrimSpace(args["previous_admin_role"].GetStringValue())
+
+	if fromUserID == toUserID {
+		return nil, status.Errorf(codes.InvalidArgument, "baton-contentful: the current and new admin are the same user")
+	}
+
+	from, err := d.getSpaceMembership(ctx, spaceID, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil || !from.Admin {
+		return nil, status.Errorf(codes.FailedPrecondition, "baton-contentful: user %s is not admin of space %s", fromUserID, spaceID)
+	}
+
+	// resolved before anything changes, so a typo doesn't leave the space with two admins
+	var previousAdminRoleID string
+	if previousAdminRole != "" {
+		previousAdminRoleID, err = newSpaceBuilder(d.client, nil, d.maxConcurrency, nil, nil, nil).cacheGetRoleID(ctx, spaceID, previousAdminRole)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "baton-contentful: unknown role %s in space %s: %v", previousAdminRole, spaceID, err)
+		}
+	}
+
+	to, err := d.getSpaceMembership(ctx, spaceID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		resUser, err := d.client.SearchUsers(ctx, toUserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(resUser.Items) == 0 {
+			return nil, status.Errorf(codes.NotFound, "baton-contentful: no user found for ID %s", toUserID)
+		}
+
+		_, err = d.client.CreateSpaceMembership(ctx, spaceID, resUser.Items[0].Email, "", true)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to make user %s admin of space %s: %w", toUserID, spaceID, err)
+		}
+	} else if !to.Admin {
+		to.Admin = true
+		to.Roles = nil
+		_, err = d.client.UpdateSpaceMembership(ctx, to)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to make user %s admin of space %s: %w", toUserID, spaceID, err)
+		}
+	}
+
+	if previousAdminRoleID == "" {
+		err = d.client.DeleteSpaceMembership(ctx, spaceID, from.Sys.ID)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to remove user %s from space %s: %w", fromUserID, spaceID, err)
+		}
+		return successResult(), nil
+	}
+
+	from.Admin = false
+	from.Roles = []client.LinkRole{
+		{
+			Sys: client.LinkSys{
+				Type:     "Link",
+				LinkType: "Role",
+				ID:       previousAdminRoleID,
+			},
+		},
+	}
+	_, err = d.client.UpdateSpaceMembership(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to update space membership of user %s: %w", fromUserID, err)
+	}
+
+	return successResult(), nil
+}
+
+func (d *Connector) getOrganizationMembership(ctx context.Context, userID string) (*client.OrganizationMembership, error) {
+	res, err := d.client.GetOrganizationMembershipByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to get org membership of user %s: %w", userID, err)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, status.Errorf(codes.NotFound, "baton-contentful: user %s is not a member of the organization", userID)
+	}
+
+	return &res.Items[0], nil
+}
+
+func (d *Connector) setRestrictedModeExemption(ctx context.Context, args map[string]*structpb.Value) (*structpb.Struct, error) {
+	userID := args["user_id"].GetStringValue()
+
+	orgMembership, err := d.getOrganizationMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgMembership.IsExemptFromRestrictedMode = args["exempt"].GetBoolValue()
+	_, err = d.client.UpdateOrganizationMembership(ctx, orgMembership)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to update org membership of user %s: %w", userID, err)
+	}
+
+	return successResult(), nil
+}
@@ -0,0 +1,96 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestValidateActionArgs(t *testing.T) {
+	schema := (&Connector{}).actions()[actionSetRestrictedExemption].schema
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			args: map[string]interface{}{"user_id": "user-1", "exempt": true},
+		},
+		{
+			name:    "missing required argument",
+			args:    map[string]interface{}{"user_id": "user-1"},
+			wantErr: true,
+		},
+		{
+			name:    "blank required string",
+			args:    map[string]interface{}{"user_id": " ", "exempt": false},
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			args:    map[string]interface{}{"user_id": "user-1", "exempt": "yes"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown argument",
+			args:    map[string]interface{}{"user_id": "user-1", "exempt": true, "role": "admin"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := structpb.NewStruct(tt.args)
+			require.NoError(t, err)
+
+			err = validateActionArgs(schema, args.GetFields())
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestResendInvitationKeepsThePendingRole(t *testing.T) {
+	s := newFakeContentful(t)
+	d := &Connector{client: s.Client(t)}
+	invitation, err := d.client.CreateInvitation(context.Background(), &client.CreateInvitationBody{Email: "margaret@example.com", Role: orgAdmin})
+	require.NoError(t, err)
+	resend := func(args map[string]interface{}) string {
+		fields, err := structpb.NewStruct(args)
+		require.NoError(t, err)
+		_, err = d.resendInvitation(context.Background(), fields.GetFields())
+		require.NoError(t, err)
+
+		s.Lock()
+		defer s.Unlock()
+		i := slices.IndexFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool {
+			return m.Sys.ID == invitation.Sys.OrganizationMembership.Sys.ID
+		})
+		require.GreaterOrEqual(t, i, 0)
+		return s.OrganizationMemberships[i].Role
+	}
+
+	require.Equal(t, orgAdmin, resend(map[string]interface{}{"email": "Margaret@example.com"}))
+	require.Equal(t, orgDeveloper, resend(map[string]interface{}{"email": "margaret@example.com", "role": orgDeveloper}))
+
+	// without a pending invitation, the email is invited as a member
+	fields, err := structpb.NewStruct(map[string]interface{}{"email": "alan@example.com"})
+	require.NoError(t, err)
+	_, err = d.resendInvitation(context.Background(), fields.GetFields())
+	require.NoError(t, err)
+	s.Lock()
+	defer s.Unlock()
+	require.Equal(t, orgMember, s.OrganizationMemberships[len(s.OrganizationMemberships)-1].Role)
+}
@@ -0,0 +1,900 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/conductorone/baton-contentful/pkg/client/clienttest"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const fakeOrgID = "org-acme"
+
+func fakeLink(linkType, id string) client.Link {
+	return client.Link{Sys: client.LinkSys{Type: "Link", LinkType: linkType, ID: id}}
+}
+
+func fakeUser(id, firstName, email string, twoFAEnabled bool) client.User {
+	return client.User{
+		FirstName:    firstName,
+		LastName:     "Test",
+		Email:        email,
+		Activated:    true,
+		Confirmed:    true,
+		TwoFAEnabled: twoFAEnabled,
+		Sys:          client.SystemInfo{Type: "User", ID: id},
+	}
+}
+
+func fakeOrgMembership(id, userID, role, status string) client.OrganizationMembership {
+	return client.OrganizationMembership{
+		Role: role,
+		Sys:  client.SystemInfo{Type: "OrganizationMembership", ID: id, Version: 1, Status: status, User: fakeLink("User", userID)},
+	}
+}
+
+func fakeTeamMembership(id, teamID, userID, orgMembershipID string) client.TeamMembership {
+	return client.TeamMembership{
+		Sys: client.SystemInfo{
+			Type:                   "TeamMembership",
+			ID:                     id,
+			Team:                   fakeLink("Team", teamID),
+			User:                   fakeLink("User", userID),
+			OrganizationMembership: fakeLink("OrganizationMembership", orgMembershipID),
+		},
+	}
+}
+
+func fakeSpaceMembership(id, userID string, admin bool, roleIDs ...string) client.SpaceMembership {
+	spaceMembership := client.SpaceMembership{
+		Admin: admin,
+		Sys:   client.SystemInfo{Type: "SpaceMembership", ID: id, Version: 1, Space: fakeLink("Space", "space-blog"), User: fakeLink("User", userID)},
+	}
+	for _, roleID := range roleIDs {
+		spaceMembership.Roles = append(spaceMembership.Roles, client.LinkRole{Sys: fakeLink("Role", roleID).Sys})
+	}
+	return spaceMembership
+}
+
+// newFakeContentful serves an organization with an owner, an admin without 2FA, a member and an invitee who
+// hasn't accepted yet, two teams, and a blog space with an editor and a translator role. The token can see a
+// space and an organization it isn't configured with too.
+func newFakeContentful(t *testing.T) *clienttest.Server {
+	s := clienttest.NewServer(t, fakeOrgID)
+
+	s.Organizations = []client.Organization{
+		{Name: "Acme", Sys: client.SystemInfo{Type: "Organization", ID: fakeOrgID}},
+		{Name: "Partner", Sys: client.SystemInfo{Type: "Organization", ID: "org-partner"}},
+	}
+	s.IdentityProviders[fakeOrgID] = client.IdentityProvider{SSOName: "acme", Enabled: true}
+	s.Users = []client.User{
+		fakeUser("user-ada", "Ada", "ada@example.com", true),
+		fakeUser("user-grace", "Grace", "grace@example.com", false),
+		fakeUser("user-linus", "Linus", "linus@example.com", true),
+	}
+	s.OrganizationMemberships = []client.OrganizationMembership{
+		fakeOrgMembership("om-ada", "user-ada", orgOwner, orgMembershipActive),
+		fakeOrgMembership("om-grace", "user-grace", orgAdmin, orgMembershipActive),
+		fakeOrgMembership("om-linus", "user-linus", orgMember, orgMembershipActive),
+		fakeOrgMembership("om-invitee", "user-invitee", orgMember, orgMembershipPending),
+	}
+	s.Teams = []client.Team{
+		{Name: "Editors", Sys: client.SystemInfo{Type: "Team", ID: "team-editors"}},
+		{Name: "Writers", Sys: client.SystemInfo{Type: "Team", ID: "team-writers"}},
+	}
+	s.TeamMemberships = []client.TeamMembership{
+		fakeTeamMembership("tm-ada", "team-editors", "user-ada", "om-ada"),
+		fakeTeamMembership("tm-linus", "team-writers", "user-linus", "om-linus"),
+	}
+	s.Spaces = []client.Space{
+		{Name: "Blog", Sys: client.SystemInfo{Type: "Space", ID: "space-blog", Org: fakeLink("Organization", fakeOrgID)}},
+		{Name: "Partner site", Sys: client.SystemInfo{Type: "Space", ID: "space-partner", Org: fakeLink("Organization", "org-partner")}},
+	}
+	s.Roles["space-blog"] = []client.Role{
+		{
+			Name:        "Editor",
+			Policies:    []client.Policy{decodePolicy(t, `{"effect": "allow", "actions": "all", "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`)},
+			Permissions: client.Permissions{ContentModel: []any{"read"}, Settings: []any{}, ContentDelivery: []any{}},
+			Sys:         client.SystemInfo{Type: "Role", ID: "role-editor", Version: 1},
+		},
+		{
+			Name: "Translator DE",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": ["read"], "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+				decodePolicy(t, `{"effect": "allow", "actions": ["update"], "constraint": {"and": [
+					{"equals": [{"doc": "sys.type"}, "Entry"]},
+					{"paths": [{"doc": "fields.%.de-DE"}]}
+				]}}`),
+			},
+			Permissions: client.Permissions{ContentModel: []any{"read"}, Settings: []any{}, ContentDelivery: []any{}},
+			Sys:         client.SystemInfo{Type: "Role", ID: "role-translator", Version: 1},
+		},
+	}
+	s.SpaceMemberships = []client.SpaceMembership{
+		fakeSpaceMembership("sm-ada", "user-ada", true),
+		fakeSpaceMembership("sm-grace", "user-grace", false, "role-editor"),
+		fakeSpaceMembership("sm-linus", "user-linus", false, "role-translator"),
+		fakeSpaceMembership("sm-invitee", "user-invitee", false, "role-editor"),
+	}
+	s.Environments["space-blog"] = []client.Environment{
+		{Name: "main", Sys: client.EnvironmentSystemInfo{Type: "Environment", ID: "main", Status: fakeLink("Status", "ready")}},
+		{Name: "staging", Sys: client.EnvironmentSystemInfo{Type: "Environment", ID: "staging", Status: fakeLink("Status", "ready")}},
+	}
+	s.Locales["space-blog/main"] = []client.Locale{
+		{Name: "English (United States)", Code: "en-US", Default: true, Sys: client.SystemInfo{Type: "Locale", ID: "locale-en"}},
+		{Name: "German (Germany)", Code: "de-DE", FallbackCode: "en-US", Sys: client.SystemInfo{Type: "Locale", ID: "locale-de"}},
+	}
+	s.ContentTypes["space-blog/main"] = []client.ContentType{
+		{Name: "Blog post", Fields: []client.ContentTypeField{{ID: "title", Name: "Title", Type: "Symbol"}}, Sys: client.SystemInfo{Type: "ContentType", ID: "blogPost"}},
+	}
+	s.EnvironmentAliases["space-blog"] = []client.EnvironmentAlias{
+		{Environment: fakeLink("Environment", "main"), Sys: client.SystemInfo{Type: "EnvironmentAlias", ID: "master"}},
+	}
+	s.APIKeys["space-blog"] = []client.APIKey{
+		{Name: "Website", Environments: []client.Link{fakeLink("Environment", "master")}, Sys: client.SystemInfo{Type: "ApiKey", ID: "key-website"}},
+	}
+
+	return s
+}
+
+// fakeSyncers returns the builders of a connector of the fake Contentful by resource type.
+func fakeSyncers(t *testing.T, s *clienttest.Server) map[string]connectorbuilder.ResourceSyncer {
+	c := s.Client(t)
+	d := &Connector{
+		client:    c,
+		riskRules: defaultRiskRules,
+		directory: newUserDirectory(c),
+	}
+
+	rv := make(map[string]connectorbuilder.ResourceSyncer)
+	for _, syncer := range d.ResourceSyncers(context.Background()) {
+		rv[syncer.ResourceType(context.Background()).Id] = syncer
+	}
+	return rv
+}
+
+// collectPages calls page with the token it returned last until it returns no token.
+func collectPages[T any](t *testing.T, page func(pToken *pagination.Token) ([]T, string, annotations.Annotations, error)) []T {
+	var rv []T
+	pToken := &pagination.Token{}
+	for range 100 {
+		items, next, _, err := page(pToken)
+		require.NoError(t, err)
+		rv = append(rv, items...)
+		if next == "" {
+			return rv
+		}
+		pToken = &pagination.Token{Token: next}
+	}
+	require.FailNow(t, "too many pages")
+	return nil
+}
+
+func listResources(t *testing.T, syncer connectorbuilder.ResourceSyncer, parent *v2.ResourceId) []*v2.Resource {
+	return collectPages(t, func(pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+		return syncer.List(context.Background(), parent, pToken)
+	})
+}
+
+// findResource lists the resources of the syncer under the parent and returns the one with the ID.
+func findResource(t *testing.T, syncer connectorbuilder.ResourceSyncer, parent *v2.ResourceId, id string) *v2.Resource {
+	resources := listResources(t, syncer, parent)
+	i := slices.IndexFunc(resources, func(r *v2.Resource) bool { return r.Id.Resource == id })
+	require.GreaterOrEqual(t, i, 0, "resource %s not listed", id)
+	return resources[i]
+}
+
+func fakeResourceID(t *testing.T, resourceType *v2.ResourceType, id string) *v2.ResourceId {
+	rv, err := resourceSdk.NewResourceID(resourceType, id)
+	require.NoError(t, err)
+	return rv
+}
+
+// grantString is the entitlement and the principal of a grant.
+func grantString(g *v2.Grant) string {
+	return g.Entitlement.Id + " " + g.Principal.Id.ResourceType + ":" + g.Principal.Id.Resource
+}
+
+type builderCase struct {
+	name         string
+	resourceType *v2.ResourceType
+	parent       string
+	id           string
+}
+
+func (c builderCase) parentID(t *testing.T) *v2.ResourceId {
+	switch {
+	case c.parent == "":
+		return nil
+	case c.resourceType == localeResourceType || c.resourceType == contentTypeResourceType:
+		return fakeResourceID(t, environmentResourceType, c.parent)
+	default:
+		return fakeResourceID(t, spaceResourceType, c.parent)
+	}
+}
+
+func TestBuildersList(t *testing.T) {
+	tests := []struct {
+		builderCase
+		want []string
+	}{
+		{builderCase{name: "users", resourceType: userResourceType}, []string{"user-ada", "user-grace", "user-linus"}},
+		{builderCase{name: "invitations", resourceType: invitationResourceType}, []string{"user-invitee"}},
+		{builderCase{name: "spaces", resourceType: spaceResourceType}, []string{"space-blog", "space-partner"}},
+		{builderCase{name: "organizations", resourceType: orgResourceType}, []string{fakeOrgID, "org-partner"}},
+		{builderCase{name: "teams", resourceType: teamResourceType}, []string{"team-editors", "team-writers"}},
+		{builderCase{name: "environments", resourceType: environmentResourceType, parent: "space-blog"}, []string{"space-blog/main", "space-blog/staging"}},
+		{builderCase{name: "environments without parent", resourceType: environmentResourceType}, nil},
+		{builderCase{name: "locales", resourceType: localeResourceType, parent: "space-blog/main"}, []string{"space-blog/main/en-US", "space-blog/main/de-DE"}},
+		{builderCase{name: "content types", resourceType: contentTypeResourceType, parent: "space-blog/main"}, []string{"space-blog/main/blogPost"}},
+		{builderCase{name: "environment aliases", resourceType: environmentAliasResourceType, parent: "space-blog"}, []string{"space-blog/master"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeContentful(t)
+			// so every listing takes more than one page
+			s.PageSize = 1
+			syncer := fakeSyncers(t, s)[tt.resourceType.Id]
+
+			var got []string
+			for _, r := range listResources(t, syncer, tt.parentID(t)) {
+				got = append(got, r.Id.Resource)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildersListProfiles(t *testing.T) {
+	s := newFakeContentful(t)
+	syncers := fakeSyncers(t, s)
+
+	org := findResource(t, syncers[orgResourceType.Id], nil, fakeOrgID)
+	groupTrait, err := resourceSdk.GetGroupTrait(org)
+	require.NoError(t, err)
+	profile := groupTrait.GetProfile().AsMap()
+	require.Equal(t, true, profile["ssoEnabled"])
+	require.EqualValues(t, 4, profile["seats"])
+	require.EqualValues(t, 1, profile["seatsOwner"])
+	require.EqualValues(t, 1, profile["spaceCount"])
+
+	user := findResource(t, syncers[userResourceType.Id], nil, "user-grace")
+	userTrait, err := resourceSdk.GetUserTrait(user)
+	require.NoError(t, err)
+	require.False(t, userTrait.GetMfaStatus().GetMfaEnabled())
+	require.Equal(t, v2.UserTrait_Status_STATUS_ENABLED, userTrait.GetStatus().GetStatus())
+}
+
+func TestBuildersListErrors(t *testing.T) {
+	tests := []struct {
+		builderCase
+		method     string
+		path       string
+		statusCode int
+		wantCode   codes.Code
+	}{
+		{
+			builderCase: builderCase{name: "users unavailable", resourceType: userResourceType},
+			method:      http.MethodGet,
+			path:        "/organizations/org-acme/users",
+			statusCode:  http.StatusServiceUnavailable,
+			wantCode:    codes.Unavailable,
+		},
+		{
+			builderCase: builderCase{name: "invitations without access", resourceType: invitationResourceType},
+			method:      http.MethodGet,
+			path:        "/organizations/org-acme/organization_memberships",
+			statusCode:  http.StatusForbidden,
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			builderCase: builderCase{name: "spaces rate limited", resourceType: spaceResourceType},
+			method:      http.MethodGet,
+			path:        "/spaces",
+			statusCode:  http.StatusTooManyRequests,
+			wantCode:    codes.Unavailable,
+		},
+		{
+			builderCase: builderCase{name: "organizations without a valid token", resourceType: orgResourceType},
+			method:      http.MethodGet,
+			path:        "/organizations",
+			statusCode:  http.StatusUnauthorized,
+			wantCode:    codes.Unauthenticated,
+		},
+		{
+			builderCase: builderCase{name: "teams failing", resourceType: teamResourceType},
+			method:      http.MethodGet,
+			path:        "/organizations/org-acme/teams",
+			statusCode:  http.StatusInternalServerError,
+			wantCode:    codes.Unavailable,
+		},
+		{
+			builderCase: builderCase{name: "environments of a deleted space", resourceType: environmentResourceType, parent: "space-blog"},
+			method:      http.MethodGet,
+			path:        "/spaces/space-blog/environments",
+			statusCode:  http.StatusNotFound,
+			wantCode:    codes.NotFound,
+		},
+		{
+			builderCase: builderCase{name: "locales failing", resourceType: localeResourceType, parent: "space-blog/main"},
+			method:      http.MethodGet,
+			path:        "/spaces/space-blog/environments/main/locales",
+			statusCode:  http.StatusInternalServerError,
+			wantCode:    codes.Unavailable,
+		},
+		{
+			builderCase: builderCase{name: "content types failing", resourceType: contentTypeResourceType, parent: "space-blog/main"},
+			method:      http.MethodGet,
+			path:        "/spaces/space-blog/environments/main/content_types",
+			statusCode:  http.StatusInternalServerError,
+			wantCode:    codes.Unavailable,
+		},
+		{
+			builderCase: builderCase{name: "API keys failing", resourceType: environmentAliasResourceType, parent: "space-blog"},
+			method:      http.MethodGet,
+			path:        "/spaces/space-blog/api_keys",
+			statusCode:  http.StatusForbidden,
+			wantCode:    codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeContentful(t)
+			s.Fail(tt.method, tt.path, tt.statusCode)
+			syncer := fakeSyncers(t, s)[tt.resourceType.Id]
+
+			_, _, _, err := syncer.List(context.Background(), tt.parentID(t), &pagination.Token{})
+			require.Error(t, err)
+			require.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestBuildersEntitlements(t *testing.T) {
+	tests := []struct {
+		builderCase
+		want []string
+	}{
+		{builderCase{name: "user", resourceType: userResourceType, id: "user-ada"}, nil},
+		{builderCase{name: "invitation", resourceType: invitationResourceType, id: "user-invitee"}, nil},
+		{builderCase{name: "space", resourceType: spaceResourceType, id: "space-blog"}, []string{
+			"space:space-blog:admin",
+			"space:space-blog:Editor",
+			"space:space-blog:Translator DE",
+		}},
+		{builderCase{name: "organization", resourceType: orgResourceType, id: fakeOrgID}, []string{
+			"organization:org-acme:owner",
+			"organization:org-acme:admin",
+			"organization:org-acme:developer",
+			"organization:org-acme:member",
+		}},
+		{builderCase{name: "team", resourceType: teamResourceType, id: "team-editors"}, []string{"team:team-editors:member"}},
+		{builderCase{name: "environment", resourceType: environmentResourceType, parent: "space-blog", id: "space-blog/main"}, nil},
+		{builderCase{name: "locale", resourceType: localeResourceType, parent: "space-blog/main", id: "space-blog/main/de-DE"}, []string{"locale:space-blog/main/de-DE:edit"}},
+		{builderCase{name: "content type", resourceType: contentTypeResourceType, parent: "space-blog/main", id: "space-blog/main/blogPost"}, []string{
+			"content_type:space-blog/main/blogPost:read",
+			"content_type:space-blog/main/blogPost:create",
+			"content_type:space-blog/main/blogPost:update",
+			"content_type:space-blog/main/blogPost:publish",
+		}},
+		{builderCase{name: "environment alias", resourceType: environmentAliasResourceType, parent: "space-blog", id: "space-blog/master"}, []string{"environment_alias:space-blog/master:manage"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeContentful(t)
+			s.PageSize = 1
+			syncer := fakeSyncers(t, s)[tt.resourceType.Id]
+			resource := findResource(t, syncer, tt.parentID(t), tt.id)
+
+			var got []string
+			for _, e := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+				return syncer.Entitlements(context.Background(), resource, pToken)
+			}) {
+				got = append(got, e.Id)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildersGrants(t *testing.T) {
+	tests := []struct {
+		builderCase
+		want []string
+	}{
+		{builderCase{name: "user", resourceType: userResourceType, id: "user-ada"}, nil},
+		{builderCase{name: "invitation", resourceType: invitationResourceType, id: "user-invitee"}, nil},
+		{builderCase{name: "space", resourceType: spaceResourceType, id: "space-blog"}, []string{
+			"space:space-blog:admin user:user-ada",
+			"space:space-blog:Editor user:user-grace",
+			"space:space-blog:Translator DE user:user-linus",
+			"space:space-blog:Editor invitation:user-invitee",
+		}},
+		{builderCase{name: "organization", resourceType: orgResourceType, id: fakeOrgID}, []string{
+			"organization:org-acme:owner user:user-ada",
+			"organization:org-acme:admin user:user-grace",
+			"organization:org-acme:member user:user-linus",
+			"organization:org-acme:member user:user-invitee",
+		}},
+		{builderCase{name: "team", resourceType: teamResourceType, id: "team-editors"}, []string{"team:team-editors:member user:user-ada"}},
+		{builderCase{name: "environment", resourceType: environmentResourceType, parent: "space-blog", id: "space-blog/main"}, nil},
+		{builderCase{name: "locale edited through a role", resourceType: localeResourceType, parent: "space-blog/main", id: "space-blog/main/de-DE"}, []string{
+			"locale:space-blog/main/de-DE:edit space:space-blog",
+		}},
+		{builderCase{name: "locale without restricted roles", resourceType: localeResourceType, parent: "space-blog/main", id: "space-blog/main/en-US"}, nil},
+		{builderCase{name: "content type", resourceType: contentTypeResourceType, parent: "space-blog/main", id: "space-blog/main/blogPost"}, []string{
+			"content_type:space-blog/main/blogPost:read space:space-blog",
+			"content_type:space-blog/main/blogPost:create space:space-blog",
+			"content_type:space-blog/main/blogPost:update space:space-blog",
+			"content_type:space-blog/main/blogPost:publish space:space-blog",
+		}},
+		{builderCase{name: "environment alias", resourceType: environmentAliasResourceType, parent: "space-blog", id: "space-blog/master"}, []string{
+			"environment_alias:space-blog/master:manage space:space-blog",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeContentful(t)
+			s.PageSize = 1
+			syncer := fakeSyncers(t, s)[tt.resourceType.Id]
+			resource := findResource(t, syncer, tt.parentID(t), tt.id)
+
+			grantableTo := make(map[string][]string)
+			for _, e := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+				return syncer.Entitlements(context.Background(), resource, pToken)
+			}) {
+				grantableTo[e.Id] = resourceTypeIDs(e.GrantableTo)
+			}
+
+			var got []string
+			for _, g := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+				return syncer.Grants(context.Background(), resource, pToken)
+			}) {
+				got = append(got, grantString(g))
+				// every grant's principal is one the entitlement declares
+				require.Contains(t, grantableTo[g.Entitlement.Id], g.Principal.Id.ResourceType, grantString(g))
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSpacesListMembershipsOncePerSync(t *testing.T) {
+	s := newFakeContentful(t)
+	o := newSpaceBuilder(s.Client(t), nil, 2, nil, nil, nil)
+	var fetches atomic.Int32
+	fetch := o.memberships.fetch
+	o.memberships.fetch = func(ctx context.Context, orgID string) (map[string][]client.SpaceMembership, error) {
+		fetches.Add(1)
+		return fetch(ctx, orgID)
+	}
+
+	for range 2 {
+		resource := findResource(t, o, nil, "space-blog")
+		grants := collectPages(t, func(pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+			return o.Grants(context.Background(), resource, pToken)
+		})
+		require.Len(t, grants, 4)
+	}
+	require.EqualValues(t, 2, fetches.Load())
+}
+
+func TestInvitationsListTheDirectoryOncePerSync(t *testing.T) {
+	s := newFakeContentful(t)
+	directory := newUserDirectory(s.Client(t))
+	var fetches atomic.Int32
+	fetch := directory.cache.fetch
+	directory.cache.fetch = func(ctx context.Context, orgID string) (*userDirectory, error) {
+		fetches.Add(1)
+		return fetch(ctx, orgID)
+	}
+	invitations := newInvitationBuilder(directory)
+	spaces := newSpaceBuilder(s.Client(t), nil, 2, directory.get, nil, nil)
+
+	for range 2 {
+		require.Len(t, listResources(t, invitations, nil), 1)
+		resource := findResource(t, spaces, nil, "space-blog")
+		grants := collectPages(t, func(pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+			return spaces.Grants(context.Background(), resource, pToken)
+		})
+		require.Equal(t, invitationResourceType.Id, grants[len(grants)-1].Principal.Id.ResourceType)
+	}
+	require.EqualValues(t, 2, fetches.Load())
+}
+
+func TestSpaceGrantsAnnotatePendingInvitations(t *testing.T) {
+	s := newFakeContentful(t)
+	spaces := fakeSyncers(t, s)[spaceResourceType.Id]
+	resource := findResource(t, spaces, nil, "space-blog")
+
+	for _, e := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+		return spaces.Entitlements(context.Background(), resource, pToken)
+	}) {
+		require.Equal(t, []string{userResourceType.Id, invitationResourceType.Id}, resourceTypeIDs(e.GrantableTo), e.Id)
+	}
+
+	pending := make(map[string]bool)
+	for _, g := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+		return spaces.Grants(context.Background(), resource, pToken)
+	}) {
+		annos := annotations.Annotations(g.Annotations)
+		warning := &contentfulv1.PendingInvitation{}
+		ok, err := annos.Pick(warning)
+		require.NoError(t, err)
+		if ok {
+			require.Equal(t, pendingInvitationWarning, warning.Warning)
+		}
+		pending[g.Principal.Id.ResourceType+":"+g.Principal.Id.Resource] = ok
+	}
+	for principal, ok := range pending {
+		require.Equal(t, principal == "invitation:user-invitee", ok, principal)
+	}
+	require.Contains(t, pending, "invitation:user-invitee")
+}
+
+func resourceTypeIDs(resourceTypes []*v2.ResourceType) []string {
+	rv := make([]string, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		rv = append(rv, resourceType.Id)
+	}
+	return rv
+}
+
+func TestSpacesDeleteIsUnimplemented(t *testing.T) {
+	s := newFakeContentful(t)
+	o := newSpaceBuilder(s.Client(t), nil, 2, nil, nil, nil)
+
+	_, err := o.Delete(context.Background(), fakeResourceID(t, spaceResourceType, "space-blog"))
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+	s.Lock()
+	require.True(t, slices.ContainsFunc(s.Spaces, func(space client.Space) bool { return space.Sys.ID == "space-blog" }))
+	s.Unlock()
+}
+
+func TestOrgGrantsFlagPrivilegedWithoutMFA(t *testing.T) {
+	s := newFakeContentful(t)
+	o := newOrgBuilder(s.Client(t), true, nil, defaultRiskRules)
+	resource := findResource(t, o, nil, fakeOrgID)
+
+	flagged := make(map[string]bool)
+	for _, g := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+		return o.Grants(context.Background(), resource, pToken)
+	}) {
+		annos := annotations.Annotations(g.Annotations)
+		warning := &contentfulv1.PrivilegedWithoutMFA{}
+		ok, err := annos.Pick(warning)
+		require.NoError(t, err)
+		if ok {
+			require.Equal(t, privilegedWithoutMFAWarning, warning.Warning)
+		}
+		flagged[grantString(g)] = ok
+	}
+	require.Equal(t, map[string]bool{
+		"organization:org-acme:owner user:user-ada":    false,
+		"organization:org-acme:admin user:user-grace":  true,
+		"organization:org-acme:member user:user-linus": false,
+		// members aren't privileged, whether they have 2FA or not
+		"organization:org-acme:member user:user-invitee": false,
+	}, flagged)
+}
+
+func TestOrgGrantsListMFAAgainOnceExpired(t *testing.T) {
+	s := newFakeContentful(t)
+	o := newOrgBuilder(s.Client(t), true, nil, defaultRiskRules)
+	var fetches atomic.Int32
+	fetch := o.mfa.fetch
+	o.mfa.fetch = func(ctx context.Context, orgID string) (map[string]bool, error) {
+		fetches.Add(1)
+		return fetch(ctx, orgID)
+	}
+	now := time.Now()
+	o.mfa.now = func() time.Time {
+		return now
+	}
+	resource := findResource(t, o, nil, fakeOrgID)
+	grants := func() {
+		collectPages(t, func(pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+			return o.Grants(context.Background(), resource, pToken)
+		})
+	}
+
+	grants()
+	grants()
+	require.EqualValues(t, 1, fetches.Load())
+
+	now = now.Add(mfaCacheTTL)
+	grants()
+	require.EqualValues(t, 2, fetches.Load())
+}
+
+func TestUsersCreateAccountReturnsInvitation(t *testing.T) {
+	s := newFakeContentful(t)
+	accounts, ok := fakeSyncers(t, s)[userResourceType.Id].(connectorbuilder.AccountManager)
+	require.True(t, ok)
+
+	profile, err := structpb.NewStruct(map[string]any{"firstName": "Margaret"})
+	require.NoError(t, err)
+	res, _, annos, err := accounts.CreateAccount(context.Background(), &v2.AccountInfo{Login: "margaret@example.com", Profile: profile}, nil)
+	require.NoError(t, err)
+	require.IsType(t, &v2.CreateAccountResponse_ActionRequiredResult{}, res)
+
+	invitation := &contentfulv1.Invitation{}
+	ok, err = annos.Pick(invitation)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NotEmpty(t, invitation.Id)
+	require.Contains(t, invitation.InvitationUrl, invitation.Id)
+
+	s.Lock()
+	defer s.Unlock()
+	i := slices.IndexFunc(s.OrganizationMemberships, func(m client.OrganizationMembership) bool {
+		return m.Sys.ID == invitation.OrganizationMembershipId
+	})
+	require.GreaterOrEqual(t, i, 0)
+	require.Equal(t, orgMembershipPending, s.OrganizationMemberships[i].Sys.Status)
+	require.Equal(t, invitation.UserId, s.OrganizationMemberships[i].Sys.User.Sys.ID)
+}
+
+// spaceMembershipOf returns the membership of the user in the blog space, or nil.
+func spaceMembershipOf(s *clienttest.Server, userID string) *client.SpaceMembership {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, spaceMembership := range s.SpaceMemberships {
+		if spaceMembership.Sys.Space.Sys.ID == "space-blog" && spaceMembership.Sys.User.Sys.ID == userID {
+			return &spaceMembership
+		}
+	}
+	return nil
+}
+
+func teamsOf(s *clienttest.Server, orgMembershipID string) []string {
+	s.Lock()
+	defer s.Unlock()
+
+	var rv []string
+	for _, teamMembership := range s.TeamMemberships {
+		if teamMembership.Sys.OrganizationMembership.Sys.ID == orgMembershipID {
+			rv = append(rv, teamMembership.Sys.Team.Sys.ID)
+		}
+	}
+	return rv
+}
+
+func TestBuildersGrant(t *testing.T) {
+	tests := []struct {
+		builderCase
+		entitlement string
+		principal   string
+		wantErr     bool
+		wantCode    codes.Code
+		check       func(t *testing.T, s *clienttest.Server)
+	}{
+		{
+			builderCase: builderCase{name: "space role to a member of the space", resourceType: spaceResourceType, id: "space-blog"},
+			entitlement: "Translator DE",
+			principal:   "user-grace",
+			wantErr:     true,
+		},
+		{
+			builderCase: builderCase{name: "space role to a user outside the space", resourceType: spaceResourceType, id: "space-blog"},
+			entitlement: "Editor",
+			principal:   "user-new",
+			check: func(t *testing.T, s *clienttest.Server) {
+				spaceMembership := spaceMembershipOf(s, "user-new")
+				require.NotNil(t, spaceMembership)
+				require.False(t, spaceMembership.Admin)
+				require.Len(t, spaceMembership.Roles, 1)
+				require.Equal(t, "role-editor", spaceMembership.Roles[0].Sys.ID)
+			},
+		},
+		{
+			builderCase: builderCase{name: "space admin", resourceType: spaceResourceType, id: "space-blog"},
+			entitlement: spaceAdmin,
+			principal:   "user-new",
+			check: func(t *testing.T, s *clienttest.Server) {
+				spaceMembership := spaceMembershipOf(s, "user-new")
+				require.NotNil(t, spaceMembership)
+				require.True(t, spaceMembership.Admin)
+			},
+		},
+		{
+			builderCase: builderCase{name: "deleted space role", resourceType: spaceResourceType, id: "space-blog"},
+			entitlement: "Publisher",
+			principal:   "user-new",
+			wantErr:     true,
+			wantCode:    codes.NotFound,
+		},
+		{
+			builderCase: builderCase{name: "organization role", resourceType: orgResourceType, id: fakeOrgID},
+			entitlement: orgAdmin,
+			principal:   "user-linus",
+			check: func(t *testing.T, s *clienttest.Server) {
+				s.Lock()
+				defer s.Unlock()
+				// org roles can't be provisioned, nothing changes
+				require.Len(t, s.OrganizationMemberships, 5)
+			},
+		},
+		{
+			builderCase: builderCase{name: "team", resourceType: teamResourceType, id: "team-editors"},
+			entitlement: teamMembership,
+			principal:   "user-linus",
+			check: func(t *testing.T, s *clienttest.Server) {
+				require.ElementsMatch(t, []string{"team-writers", "team-editors"}, teamsOf(s, "om-linus"))
+			},
+		},
+		{
+			builderCase: builderCase{name: "team to a user outside the organization", resourceType: teamResourceType, id: "team-editors"},
+			entitlement: teamMembership,
+			principal:   "user-unknown",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeContentful(t)
+			s.Users = append(s.Users, fakeUser("user-new", "Nova", "nova@example.com", true))
+			s.OrganizationMemberships = append(s.OrganizationMemberships, fakeOrgMembership("om-new", "user-new", orgMember, orgMembershipActive))
+			syncer := fakeSyncers(t, s)[tt.resourceType.Id]
+			provisioner, ok := syncer.(connectorbuilder.ResourceProvisioner)
+			require.True(t, ok)
+
+			resource := findResource(t, syncer, tt.parentID(t), tt.id)
+			principal := &v2.Resource{Id: fakeResourceID(t, userResourceType, tt.principal)}
+			entitlement := &v2.Entitlement{
+				Id:       resource.Id.ResourceType + ":" + resource.Id.Resource + ":" + tt.entitlement,
+				Resource: resource,
+				Slug:     tt.entitlement,
+			}
+
+			_, err := provisioner.Grant(context.Background(), principal, entitlement)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantCode != codes.OK {
+					require.Equal(t, tt.wantCode, status.Code(err))
+				}
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestBuildersRevoke(t *testing.T) {
+	tests := []struct {
+		builderCase
+		entitlement    string
+		principal      string
+		wantErr        bool
+		alreadyRevoked bool
+		check          func(t *testing.T, s *clienttest.Server)
+	}{
+		{
+			builderCase: builderCase{name: "space role", resourceType: spaceResourceType, id: "space-blog"},
+			entitlement: "Editor",
+			principal:   "user-grace",
+			check: func(t *testing.T, s *clienttest.Server) {
+				require.Nil(t, spaceMembershipOf(s, "user-grace"))
+			},
+		},
+		{
+			builderCase:    builderCase{name: "space role of a user outside the space", resourceType: spaceResourceType, id: "space-blog"},
+			entitlement:    "Editor",
+			principal:      "user-nobody",
+			alreadyRevoked: true,
+		},
+		{
+			builderCase: builderCase{name: "organization role", resourceType: orgResourceType, id: fakeOrgID},
+			entitlement: orgMember,
+			principal:   "user-linus",
+			check: func(t *testing.T, s *clienttest.Server) {
+				// leaving the organization leaves its teams and spaces too
+				require.Empty(t, teamsOf(s, "om-linus"))
+				require.Nil(t, spaceMembershipOf(s, "user-linus"))
+			},
+		},
+		{
+			builderCase:    builderCase{name: "organization role of a user outside the organization", resourceType: orgResourceType, id: fakeOrgID},
+			entitlement:    orgMember,
+			principal:      "user-nobody",
+			alreadyRevoked: true,
+		},
+		{
+			builderCase: builderCase{name: "team", resourceType: teamResourceType, id: "team-editors"},
+			entitlement: teamMembership,
+			principal:   "user-ada",
+			check: func(t *testing.T, s *clienttest.Server) {
+				require.Empty(t, teamsOf(s, "om-ada"))
+			},
+		},
+		{
+			builderCase:    builderCase{name: "team of a member of another team", resourceType: teamResourceType, id: "team-editors"},
+			entitlement:    teamMembership,
+			principal:      "user-linus",
+			alreadyRevoked: true,
+			check: func(t *testing.T, s *clienttest.Server) {
+				require.Equal(t, []string{"team-writers"}, teamsOf(s, "om-linus"))
+			},
+		},
+		{
+			builderCase: builderCase{name: "team of a user outside the organization", resourceType: teamResourceType, id: "team-editors"},
+			entitlement: teamMembership,
+			principal:   "user-nobody",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeContentful(t)
+			syncer := fakeSyncers(t, s)[tt.resourceType.Id]
+			provisioner, ok := syncer.(connectorbuilder.ResourceProvisioner)
+			require.True(t, ok)
+
+			resource := findResource(t, syncer, tt.parentID(t), tt.id)
+			g := &v2.Grant{
+				Entitlement: &v2.Entitlement{
+					Id:       resource.Id.ResourceType + ":" + resource.Id.Resource + ":" + tt.entitlement,
+					Resource: resource,
+					Slug:     tt.entitlement,
+				},
+				Principal: &v2.Resource{Id: fakeResourceID(t, userResourceType, tt.principal)},
+			}
+
+			annos, err := provisioner.Revoke(context.Background(), g)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.alreadyRevoked, annos.Contains(&v2.GrantAlreadyRevoked{}))
+			if tt.check != nil {
+				tt.check(t, s)
+			}
+		})
+	}
+}
+
+func TestBuildersProvisioningErrors(t *testing.T) {
+	s := newFakeContentful(t)
+	s.Fail(http.MethodDelete, "/spaces/space-blog/space_memberships/sm-grace", http.StatusInternalServerError)
+	s.Fail(http.MethodPost, "/organizations/org-acme/teams/team-editors/team_memberships", http.StatusForbidden)
+	syncers := fakeSyncers(t, s)
+
+	space := findResource(t, syncers[spaceResourceType.Id], nil, "space-blog")
+	_, err := syncers[spaceResourceType.Id].(connectorbuilder.ResourceProvisioner).Revoke(context.Background(), &v2.Grant{
+		Entitlement: &v2.Entitlement{Id: "space:space-blog:Editor", Resource: space, Slug: "Editor"},
+		Principal:   &v2.Resource{Id: fakeResourceID(t, userResourceType, "user-grace")},
+	})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.NotNil(t, spaceMembershipOf(s, "user-grace"))
+
+	team := findResource(t, syncers[teamResourceType.Id], nil, "team-editors")
+	_, err = syncers[teamResourceType.Id].(connectorbuilder.ResourceProvisioner).Grant(context.Background(),
+		&v2.Resource{Id: fakeResourceID(t, userResourceType, "user-linus")},
+		&v2.Entitlement{Id: "team:team-editors:member", Resource: team, Slug: teamMembership},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Equal(t, []string{"team-writers"}, teamsOf(s, "om-linus"))
+}
@@ -2,28 +2,57 @@ package connector
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/conductorone/baton-contentful/pkg/webhook"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 )
 
 type Connector struct {
-	client *client.Client
+	client                   *client.Client
+	flagPrivilegedWithoutMFA bool
+	webhooks                 *webhook.Buffer
+	state                    *stateStore
+	maxConcurrency           int
+	// nil unless roles are checked for drift
+	roleBaseline roleBaseline
+	riskRules    riskRules
+	// tells invitees apart from users, for the builders, the event feed and webhook events alike
+	directory *sharedDirectory
+
+	mu sync.Mutex
+	// the memberships the event feed saw last, when there is no state directory to keep them in
+	eventSnapshot *syncState[membershipState]
 }
 
 // ResourceSyncers returns a ResourceSyncer for each resource type that should be synced from the upstream service.
 func (d *Connector) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
+	spaces := newSpaceBuilder(d.client, d.state, d.maxConcurrency, d.directory.get, d.roleBaseline, d.riskRules)
 	return []connectorbuilder.ResourceSyncer{
-		newUserBuilder(d.client),
-		newSpaceBuilder(d.client),
-		newOrgBuilder(d.client),
+		newUserBuilder(d.client, spaces),
+		newInvitationBuilder(d.directory),
+		spaces,
+		newOrgBuilder(d.client, d.flagPrivilegedWithoutMFA, d.state, d.riskRules),
 		newTeamBuilder(d.client),
+		newEnvironmentBuilder(d.client),
+		newLocaleBuilder(d.client, spaces),
+		newContentTypeBuilder(d.client, spaces),
+		newEnvironmentAliasBuilder(d.client, spaces),
 	}
 }
 
+// WebhookBuffer returns the buffer the connector reads webhook events from, nil without a webhook buffer path.
+// A webhook receiver in the same process appends to it, so both go through the buffer's lock.
+func (d *Connector) WebhookBuffer() *webhook.Buffer {
+	return d.webhooks
+}
+
 // Asset takes an input AssetRef and attempts to fetch it using the connector's authenticated http client
 // It streams a response, always starting with a metadata object, following by chunked payloads for the asset.
 func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.ReadCloser, error) {
@@ -32,6 +61,7 @@ func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.R
 
 // Metadata returns metadata about the connector.
 func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
+	defaultRole := orgMember
 	return &v2.ConnectorMetadata{
 		DisplayName: "Contentful",
 		Description: "Connector for Contentful",
@@ -70,13 +100,35 @@ func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error)
 				"role": {
 					DisplayName: "Role",
 					Required:    false,
-					Description: "User's Role, can be one of 'member', 'developer', 'admin' and 'owner'",
+					Description: fmt.Sprintf("User's organization role, one of: %s. Defaults to '%s'.", strings.Join(orgRoles, ", "), orgMember),
 					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{
+							DefaultValue: &defaultRole,
+						},
 					},
-					Placeholder: "role",
+					Placeholder: strings.Join(orgRoles, " | "),
 					Order:       4,
 				},
+				"teamIds": {
+					DisplayName: "Teams",
+					Required:    false,
+					Description: "IDs of the teams the user is added to once invited.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringListField{
+						StringListField: &v2.ConnectorAccountCreationSchema_StringListField{},
+					},
+					Placeholder: "Team IDs",
+					Order:       5,
+				},
+				"spaceRoles": {
+					DisplayName: "Space Roles",
+					Required:    false,
+					Description: "Space roles the user is given once invited, each as '<space ID>:<role name>'. Use 'admin' as the role name for space admins.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringListField{
+						StringListField: &v2.ConnectorAccountCreationSchema_StringListField{},
+					},
+					Placeholder: "spaceId:Editor",
+					Order:       6,
+				},
 			},
 		},
 	}, nil
@@ -88,13 +140,60 @@ func (d *Connector) Validate(ctx context.Context) (annotations.Annotations, erro
 	return nil, nil
 }
 
+// Config configures the connector, only OrgID and Token are required.
+type Config struct {
+	OrgID string
+	Token string
+	// annotate the owner and admin grants of the organization held by users without 2FA enabled
+	FlagPrivilegedWithoutMFA bool
+	// file webhook events are buffered in for the event feed, empty when there is no webhook receiver
+	WebhookBufferPath string
+	// directory the memberships seen by the previous sync are kept in, empty to always sync every membership
+	IncrementalStateDir string
+	// YAML or JSON file of canonical space roles, empty to not check space roles for drift
+	RoleBaselinePath string
+	// YAML or JSON file of risk rules, empty for the default rules
+	RiskRulesPath string
+	// number of spaces whose roles and members are fetched at the same time, 4 unless set
+	MaxConcurrency int
+}
+
 // New returns a new instance of the connector.
-func New(ctx context.Context, orgID, token string) (*Connector, error) {
-	c, err := client.New(ctx, orgID, token)
+func New(ctx context.Context, cfg Config) (*Connector, error) {
+	c, err := client.New(ctx, cfg.OrgID, cfg.Token)
 	if err != nil {
 		return nil, err
 	}
+
+	var baseline roleBaseline
+	if cfg.RoleBaselinePath != "" {
+		baseline, err = loadRoleBaseline(cfg.RoleBaselinePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	risk := defaultRiskRules
+	if cfg.RiskRulesPath != "" {
+		risk, err = loadRiskRules(cfg.RiskRulesPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var webhooks *webhook.Buffer
+	if cfg.WebhookBufferPath != "" {
+		webhooks = webhook.NewBuffer(cfg.WebhookBufferPath)
+	}
+
 	return &Connector{
-		client: c,
+		client:                   c,
+		flagPrivilegedWithoutMFA: cfg.FlagPrivilegedWithoutMFA,
+		webhooks:                 webhooks,
+		state:                    newStateStore(cfg.IncrementalStateDir),
+		maxConcurrency:           cfg.MaxConcurrency,
+		roleBaseline:             baseline,
+		riskRules:                risk,
+		directory:                newUserDirectory(c),
 	}, nil
 }
@@ -0,0 +1,163 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// contentTypeActions are the actions on entries of a content type that have an entitlement.
+var contentTypeActions = []string{"read", "create", "update", "publish"}
+
+// contentTypeBuilder lists the content types of environments. Their entitlements are granted through the space roles
+// that allow the action on entries of the content type, and space admins.
+type contentTypeBuilder struct {
+	client *client.Client
+	spaces *spaceBuilder
+}
+
+func (o *contentTypeBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return contentTypeResourceType
+}
+
+func contentTypeResource(parentResourceID *v2.ResourceId, spaceID, environmentID string, contentType client.ContentType) (*v2.Resource, error) {
+	description := fmt.Sprintf("%d fields", len(contentType.Fields))
+	if contentType.Description != "" {
+		description = fmt.Sprintf("%s, %s", contentType.Description, description)
+	}
+
+	return resourceSdk.NewResource(
+		contentType.Name,
+		contentTypeResourceType,
+		environmentChildResourceID(spaceID, environmentID, contentType.Sys.ID),
+		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithDescription(description),
+	)
+}
+
+func (o *contentTypeBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// content types are only listed under their environment
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+	spaceID, environmentID, err := splitEnvironmentResourceID(parentResourceID.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var offset int
+	if pToken.Token != "" {
+		offset, err = strconv.Atoi(pToken.Token)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	res, err := o.client.ListContentTypes(ctx, spaceID, environmentID, offset)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list content types of environment %s: %w", parentResourceID.Resource, err)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, "", nil, nil
+	}
+	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
+
+	rv := make([]*v2.Resource, 0, len(res.Items))
+	for _, contentType := range res.Items {
+		r, err := contentTypeResource(parentResourceID, spaceID, environmentID, contentType)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create content type resource %s: %w", contentType.Sys.ID, err)
+		}
+		rv = append(rv, r)
+	}
+
+	return rv, nextOffset, nil, nil
+}
+
+func (o *contentTypeBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	rv := make([]*v2.Entitlement, 0, len(contentTypeActions))
+	for _, action := range contentTypeActions {
+		rv = append(rv, entitlement.NewPermissionEntitlement(
+			resource,
+			action,
+			entitlement.WithGrantableTo(spaceRolesGrantableTo...),
+			entitlement.WithDescription(fmt.Sprintf("Can %s %s entries, through a space role", action, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s %s entries", strings.ToUpper(action[:1])+action[1:], resource.DisplayName)),
+		))
+	}
+	return rv, "", nil, nil
+}
+
+// contentTypeRoles returns the entitlements of the space roles that allow each action on entries of the content type,
+// space admins can do all of them.
+func contentTypeRoles(spaceID, contentTypeID string, roles []client.Role) (map[string][]string, error) {
+	adminEntitlementID, err := spaceRoleEntitlementID(spaceID, spaceAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := policyDoc{
+		"sys.type":               "Entry",
+		"sys.contentType.sys.id": contentTypeID,
+	}
+	rv := make(map[string][]string, len(contentTypeActions))
+	for _, action := range contentTypeActions {
+		rv[action] = []string{adminEntitlementID}
+		for _, role := range roles {
+			if !roleAllows(role, action, doc) {
+				continue
+			}
+
+			entitlementID, err := spaceRoleEntitlementID(spaceID, role.Name)
+			if err != nil {
+				return nil, err
+			}
+			rv[action] = append(rv[action], entitlementID)
+		}
+	}
+	return rv, nil
+}
+
+// Grants grants each entitlement to the space, expanded to the members of the space roles that allow the action.
+func (o *contentTypeBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	spaceID, _, contentTypeID, err := splitEnvironmentChildResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	roles, err := o.spaces.roles.get(ctx, spaceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	byAction, err := contentTypeRoles(spaceID, contentTypeID, roles)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	rv := make([]*v2.Grant, 0, len(contentTypeActions))
+	for _, action := range contentTypeActions {
+		g, err := spaceRolesGrant(resource, action, spaceID, byAction[action])
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, g)
+	}
+	return rv, "", nil, nil
+}
+
+func newContentTypeBuilder(client *client.Client, spaces *spaceBuilder) *contentTypeBuilder {
+	return &contentTypeBuilder{
+		client: client,
+		spaces: spaces,
+	}
+}
@@ -0,0 +1,49 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentTypeRoles(t *testing.T) {
+	roles := []client.Role{
+		{
+			Name: "Editor",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": "all", "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+			},
+		},
+		{
+			Name: "Blogger",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": ["read"], "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+				decodePolicy(t, `{"effect": "allow", "actions": ["create", "update", "publish"], "constraint": {"and": [
+					{"equals": [{"doc": "sys.type"}, "Entry"]},
+					{"equals": [{"doc": "sys.contentType.sys.id"}, "blogPost"]}
+				]}}`),
+			},
+		},
+		{
+			Name: "Media",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": "all", "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Asset"]}]}}`),
+			},
+		},
+	}
+
+	byAction, err := contentTypeRoles("space-1", "blogPost", roles)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"read":    {"space:space-1:admin", "space:space-1:Editor", "space:space-1:Blogger"},
+		"create":  {"space:space-1:admin", "space:space-1:Editor", "space:space-1:Blogger"},
+		"update":  {"space:space-1:admin", "space:space-1:Editor", "space:space-1:Blogger"},
+		"publish": {"space:space-1:admin", "space:space-1:Editor", "space:space-1:Blogger"},
+	}, byAction)
+
+	byAction, err = contentTypeRoles("space-1", "page", roles)
+	require.NoError(t, err)
+	require.Equal(t, []string{"space:space-1:admin", "space:space-1:Editor", "space:space-1:Blogger"}, byAction["read"])
+	require.Equal(t, []string{"space:space-1:admin", "space:space-1:Editor"}, byAction["publish"])
+}
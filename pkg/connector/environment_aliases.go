@@ -0,0 +1,191 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const aliasManage = "manage"
+
+// environmentAliasBuilder lists the environment aliases of spaces with the environment they target. Changing the
+// target deploys the content of another environment, the manage entitlement shows who can.
+type environmentAliasBuilder struct {
+	client *client.Client
+	spaces *spaceBuilder
+}
+
+func (o *environmentAliasBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return environmentAliasResourceType
+}
+
+// roleManagesAliases tells whether the role can change the targets of environment aliases, through its
+// EnvironmentAliases or Settings permissions.
+func roleManagesAliases(role client.Role) bool {
+	for _, permission := range []any{role.Permissions.EnvironmentAliases, role.Permissions.Settings} {
+		actions := normalizeActions(permission)
+		if slices.Contains(actions, policyAllActions) || slices.Contains(actions, aliasManage) {
+			return true
+		}
+	}
+	return false
+}
+
+// listAPIKeyNames returns the names of the delivery API keys of the space by the environments and aliases they deliver.
+// The keys can only read content, they can't change the target of an alias.
+func (o *environmentAliasBuilder) listAPIKeyNames(ctx context.Context, spaceID string) (map[string][]string, error) {
+	rv := make(map[string][]string)
+	var offset int
+	for {
+		res, err := o.client.ListAPIKeys(ctx, spaceID, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list API keys of space %s: %w", spaceID, err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, apiKey := range res.Items {
+			for _, environment := range apiKey.Environments {
+				rv[environment.Sys.ID] = append(rv[environment.Sys.ID], apiKey.Name)
+			}
+		}
+
+		offset += len(res.Items)
+	}
+	return rv, nil
+}
+
+func environmentAliasResource(parentResourceID *v2.ResourceId, alias client.EnvironmentAlias, apiKeyNames []string) (*v2.Resource, error) {
+	description := fmt.Sprintf("Targets environment %s", alias.Environment.Sys.ID)
+	if len(apiKeyNames) > 0 {
+		description += fmt.Sprintf(", delivered to API keys %s", strings.Join(apiKeyNames, ", "))
+	}
+
+	return resourceSdk.NewResource(
+		alias.Sys.ID,
+		environmentAliasResourceType,
+		environmentResourceID(parentResourceID.Resource, alias.Sys.ID),
+		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithDescription(description),
+	)
+}
+
+func (o *environmentAliasBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// aliases are only listed under their space
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+	spaceID := parentResourceID.Resource
+
+	var offset int
+	var err error
+	if pToken.Token != "" {
+		offset, err = strconv.Atoi(pToken.Token)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	res, err := o.client.ListEnvironmentAliases(ctx, spaceID, offset)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list environment aliases of space %s: %w", spaceID, err)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, "", nil, nil
+	}
+	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
+
+	apiKeyNames, err := o.listAPIKeyNames(ctx, spaceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	rv := make([]*v2.Resource, 0, len(res.Items))
+	for _, alias := range res.Items {
+		r, err := environmentAliasResource(parentResourceID, alias, apiKeyNames[alias.Sys.ID])
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create environment alias resource %s: %w", alias.Sys.ID, err)
+		}
+		rv = append(rv, r)
+	}
+
+	return rv, nextOffset, nil, nil
+}
+
+func (o *environmentAliasBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			aliasManage,
+			entitlement.WithGrantableTo(spaceRolesGrantableTo...),
+			entitlement.WithDescription(fmt.Sprintf("Change the environment the %s alias targets, deploying its content", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Manage the %s alias", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// aliasManagers returns the entitlements of space admins and of the space roles that can manage aliases.
+func aliasManagers(spaceID string, roles []client.Role) ([]string, error) {
+	adminEntitlementID, err := spaceRoleEntitlementID(spaceID, spaceAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := []string{adminEntitlementID}
+	for _, role := range roles {
+		if !roleManagesAliases(role) {
+			continue
+		}
+
+		entitlementID, err := spaceRoleEntitlementID(spaceID, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, entitlementID)
+	}
+	return rv, nil
+}
+
+// Grants grants the manage entitlement to the space, expanded to its admins and the members of the space roles
+// that can manage aliases.
+func (o *environmentAliasBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	spaceID, _, err := splitEnvironmentResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	roles, err := o.spaces.roles.get(ctx, spaceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	managers, err := aliasManagers(spaceID, roles)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	g, err := spaceRolesGrant(resource, aliasManage, spaceID, managers)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return []*v2.Grant{g}, "", nil, nil
+}
+
+func newEnvironmentAliasBuilder(client *client.Client, spaces *spaceBuilder) *environmentAliasBuilder {
+	return &environmentAliasBuilder{
+		client: client,
+		spaces: spaces,
+	}
+}
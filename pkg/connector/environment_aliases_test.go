@@ -0,0 +1,25 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasManagers(t *testing.T) {
+	roles := []client.Role{
+		{Name: "Editor", Permissions: client.Permissions{ContentModel: []any{"read"}, Settings: []any{}}},
+		{Name: "Developer", Permissions: client.Permissions{ContentModel: "all", Settings: "all"}},
+		{Name: "Release manager", Permissions: client.Permissions{EnvironmentAliases: []any{"manage"}}},
+		{Name: "Environments", Permissions: client.Permissions{Environments: "all", EnvironmentAliases: []any{}}},
+	}
+
+	managers, err := aliasManagers("space-1", roles)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"space:space-1:admin",
+		"space:space-1:Developer",
+		"space:space-1:Release manager",
+	}, managers)
+}
@@ -0,0 +1,121 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// environmentResourceID is the resource ID of an environment, prefixed with its space ID.
+func environmentResourceID(spaceID, environmentID string) string {
+	return spaceID + "/" + environmentID
+}
+
+func splitEnvironmentResourceID(id string) (string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("baton-contentful: invalid environment resource ID %s", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// environmentChildResourceID is the resource ID of a locale or content type, its ID prefixed with the environment's resource ID.
+func environmentChildResourceID(spaceID, environmentID, id string) string {
+	return environmentResourceID(spaceID, environmentID) + "/" + id
+}
+
+func splitEnvironmentChildResourceID(id string) (string, string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("baton-contentful: invalid resource ID %s", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+type environmentBuilder struct {
+	client *client.Client
+}
+
+func (o *environmentBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return environmentResourceType
+}
+
+func environmentResource(spaceID string, environment client.Environment) (*v2.Resource, error) {
+	parentID, err := resourceSdk.NewResourceID(spaceResourceType, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceSdk.NewResource(
+		environment.Name,
+		environmentResourceType,
+		environmentResourceID(spaceID, environment.Sys.ID),
+		resourceSdk.WithParentResourceID(parentID),
+		resourceSdk.WithDescription(fmt.Sprintf("Environment %s, %s", environment.Sys.ID, environment.Sys.Status.Sys.ID)),
+		resourceSdk.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: localeResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: contentTypeResourceType.Id},
+		),
+	)
+}
+
+func (o *environmentBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// environments are only listed under their space
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+	spaceID := parentResourceID.Resource
+
+	var offset int
+	var err error
+	if pToken.Token != "" {
+		offset, err = strconv.Atoi(pToken.Token)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	res, err := o.client.ListEnvironments(ctx, spaceID, offset)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list environments of space %s: %w", spaceID, err)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, "", nil, nil
+	}
+	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
+
+	rv := make([]*v2.Resource, 0, len(res.Items))
+	for _, environment := range res.Items {
+		r, err := environmentResource(spaceID, environment)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create environment resource %s: %w", environment.Sys.ID, err)
+		}
+		rv = append(rv, r)
+	}
+
+	return rv, nextOffset, nil, nil
+}
+
+// Entitlements always returns an empty slice for environments.
+func (o *environmentBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for environments since they don't have any entitlements.
+func (o *environmentBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newEnvironmentBuilder(client *client.Client) *environmentBuilder {
+	return &environmentBuilder{
+		client: client,
+	}
+}
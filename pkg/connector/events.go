@@ -0,0 +1,448 @@
+package connector
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventSnapshotKey is the state the event feed keeps its membership snapshot under.
+const eventSnapshotKey = "events"
+
+// Contentful has no audit log API, so the event feed is built by diffing the org, space and team
+// memberships against the snapshot the previous call saved. The stream cursor only carries what the
+// snapshot is matched with.
+type eventFeedState struct {
+	// Watermark is the most recent sys.updatedAt seen across all memberships.
+	Watermark time.Time `json:"w"`
+	// WebhookOffset is how far the webhook buffer has been read.
+	WebhookOffset int64 `json:"o,omitempty"`
+}
+
+type membershipState struct {
+	Version int          `json:"v"`
+	Grants  []eventGrant `json:"g"`
+}
+
+type eventGrant struct {
+	ResourceType string `json:"t"`
+	ResourceID   string `json:"r"`
+	Entitlement  string `json:"e"`
+	UserID       string `json:"u"`
+	// the principal's resource type, empty for users
+	PrincipalType string `json:"p,omitempty"`
+	// whether the user is yet to accept their org invitation
+	Pending bool `json:"i,omitempty"`
+}
+
+type membershipSnapshot struct {
+	membershipState
+	updatedAt time.Time
+}
+
+func decodeEventFeedState(cursor string) (*eventFeedState, error) {
+	state := &eventFeedState{}
+	if cursor == "" {
+		return state, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: invalid event feed cursor: %w", err)
+	}
+
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: invalid event feed cursor: %w", err)
+	}
+
+	return state, nil
+}
+
+func (s *eventFeedState) encode() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("baton-contentful: failed to encode event feed cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// loadEventSnapshot returns the memberships the call that returned the cursor saw, nil when they aren't known:
+// on the first call, or when the snapshot was saved by another call since.
+func (d *Connector) loadEventSnapshot(cursor *eventFeedState) (map[string]membershipState, error) {
+	var snapshot *syncState[membershipState]
+	if d.state != nil {
+		var err error
+		snapshot, err = loadSyncState[membershipState](d.state, eventSnapshotKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		d.mu.Lock()
+		snapshot = d.eventSnapshot
+		d.mu.Unlock()
+	}
+
+	if snapshot == nil || !snapshot.Watermark.Equal(cursor.Watermark) {
+		return nil, nil
+	}
+	return snapshot.Memberships, nil
+}
+
+// saveEventSnapshot keeps the snapshot in the state directory, in memory when there is none.
+func (d *Connector) saveEventSnapshot(snapshot *syncState[membershipState]) error {
+	if d.state != nil {
+		return saveSyncState(d.state, eventSnapshotKey, snapshot)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.eventSnapshot = snapshot
+	return nil
+}
+
+// ListEvents emits grant and revoke events for membership changes since the previous call.
+// The first call only records the current memberships, and reports the ones updated after earliestEvent.
+// Events buffered by the webhook receiver are included when a webhook buffer is configured.
+func (d *Connector) ListEvents(ctx context.Context, earliestEvent *timestamppb.Timestamp, pToken *pagination.StreamToken) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	state, err := decodeEventFeedState(pToken.Cursor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	prev, err := d.loadEventSnapshot(state)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	current, err := d.snapshotMemberships(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var since time.Time
+	if earliestEvent != nil {
+		since = earliestEvent.AsTime()
+	}
+
+	events := diffMemberships(prev, state.Watermark, current, since, time.Now())
+
+	next := &eventFeedState{
+		Watermark:     state.Watermark,
+		WebhookOffset: state.WebhookOffset,
+	}
+
+	// webhooks report space membership changes as they happen, ahead of the next diff
+	if d.webhooks != nil {
+		buffered, offset, err := d.webhooks.Read(state.WebhookOffset, 0)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, event := range buffered {
+			if !since.IsZero() && event.OccurredAt.AsTime().Before(since) {
+				continue
+			}
+			events = append(events, event)
+		}
+		sortEvents(events)
+		next.WebhookOffset = offset
+	}
+
+	snapshot := &syncState[membershipState]{
+		Memberships: make(map[string]membershipState, len(current)),
+	}
+	for key, membership := range current {
+		snapshot.Memberships[key] = membership.membershipState
+		if membership.updatedAt.After(next.Watermark) {
+			next.Watermark = membership.updatedAt
+		}
+	}
+	snapshot.Watermark = next.Watermark
+
+	err = d.saveEventSnapshot(snapshot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cursor, err := next.encode()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return events, &pagination.StreamState{Cursor: cursor, HasMore: false}, nil, nil
+}
+
+// diffMemberships returns the events of the changes from the previous memberships, the ones the previous call saw
+// up to the watermark, to the current ones.
+func diffMemberships(prev map[string]membershipState, watermark time.Time, current map[string]membershipSnapshot, since, now time.Time) []*v2.Event {
+	var events []*v2.Event
+
+	// without the previous memberships there is nothing to diff against, so only report the memberships updated
+	// since the previous call, or since earliestEvent on the first call
+	if prev == nil {
+		if watermark.IsZero() && since.IsZero() {
+			return nil
+		}
+		for key, membership := range current {
+			if !watermark.IsZero() && !membership.updatedAt.After(watermark) {
+				continue
+			}
+			if membership.updatedAt.Before(since) {
+				continue
+			}
+			for _, g := range membership.Grants {
+				events = append(events, newGrantEvent(key, membership.Version, g, membership.updatedAt))
+			}
+		}
+		sortEvents(events)
+		return events
+	}
+
+	for key, membership := range current {
+		old, ok := prev[key]
+		if ok && old.Version == membership.Version && !membership.updatedAt.After(watermark) {
+			continue
+		}
+
+		for _, g := range membership.Grants {
+			if !slices.Contains(old.Grants, g) {
+				events = append(events, newGrantEvent(key, membership.Version, g, membership.updatedAt))
+			}
+		}
+		for _, g := range old.Grants {
+			if !slices.Contains(membership.Grants, g) {
+				events = append(events, newRevokeEvent(fmt.Sprintf("%s:%d", key, membership.Version), g, membership.updatedAt))
+			}
+		}
+	}
+
+	// deleted memberships don't carry a timestamp, they are reported as revoked when noticed
+	for key, old := range prev {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		for _, g := range old.Grants {
+			events = append(events, newRevokeEvent(fmt.Sprintf("%s:deleted", key), g, now))
+		}
+	}
+
+	sortEvents(events)
+	return events
+}
+
+func sortEvents(events []*v2.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		ti, tj := events[i].OccurredAt.AsTime(), events[j].OccurredAt.AsTime()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return events[i].Id < events[j].Id
+	})
+}
+
+func (g eventGrant) resource() *v2.Resource {
+	return &v2.Resource{
+		Id: &v2.ResourceId{
+			ResourceType: g.ResourceType,
+			Resource:     g.ResourceID,
+		},
+	}
+}
+
+func (g eventGrant) principal() *v2.ResourceId {
+	principalType := g.PrincipalType
+	if principalType == "" {
+		principalType = userResourceType.Id
+	}
+	return &v2.ResourceId{
+		ResourceType: principalType,
+		Resource:     g.UserID,
+	}
+}
+
+func newGrantEvent(key string, version int, g eventGrant, occurredAt time.Time) *v2.Event {
+	var grantOpts []grant.GrantOption
+	if g.Pending {
+		grantOpts = append(grantOpts, grant.WithAnnotation(&contentfulv1.PendingInvitation{
+			Warning: pendingInvitationWarning,
+		}))
+	}
+	newGrant := grant.NewGrant(g.resource(), g.Entitlement, g.principal(), grantOpts...)
+	return &v2.Event{
+		Id:         fmt.Sprintf("%s:%d:grant:%s", key, version, newGrant.Id),
+		OccurredAt: timestamppb.New(occurredAt),
+		Event: &v2.Event_GrantEvent{
+			GrantEvent: &v2.GrantEvent{
+				Grant: newGrant,
+			},
+		},
+	}
+}
+
+func newRevokeEvent(prefix string, g eventGrant, occurredAt time.Time) *v2.Event {
+	revokedEntitlement := entitlement.NewAssignmentEntitlement(g.resource(), g.Entitlement)
+	return &v2.Event{
+		Id:         fmt.Sprintf("%s:revoke:%s", prefix, grant.NewGrantID(g.principal(), revokedEntitlement)),
+		OccurredAt: timestamppb.New(occurredAt),
+		Event: &v2.Event_RevokeEvent{
+			RevokeEvent: &v2.RevokeEvent{
+				Entitlement: revokedEntitlement,
+				Principal:   &v2.Resource{Id: g.principal()},
+			},
+		},
+	}
+}
+
+func spaceMembershipGrants(ctx context.Context, spaces *spaceBuilder, spaceMembership client.SpaceMembership) ([]eventGrant, error) {
+	spaceID := spaceMembership.Sys.Space.Sys.ID
+	userID := spaceMembership.Sys.User.Sys.ID
+
+	// the same principal as the space's grants, so invitees are told apart from users
+	principalID, pending, err := spaces.principal(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", userID, err)
+	}
+	var principalType string
+	if principalID.ResourceType != userResourceType.Id {
+		principalType = principalID.ResourceType
+	}
+
+	if spaceMembership.Admin {
+		return []eventGrant{{
+			ResourceType:  spaceResourceType.Id,
+			ResourceID:    spaceID,
+			Entitlement:   spaceAdmin,
+			UserID:        userID,
+			PrincipalType: principalType,
+			Pending:       pending,
+		}}, nil
+	}
+
+	grants := make([]eventGrant, 0, len(spaceMembership.Roles))
+	for _, role := range spaceMembership.Roles {
+		roleName, err := spaces.cacheGetRoleName(ctx, spaceID, role.Sys.ID)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to get role name for role ID %s: %w", role.Sys.ID, err)
+		}
+		grants = append(grants, eventGrant{
+			ResourceType:  spaceResourceType.Id,
+			ResourceID:    spaceID,
+			Entitlement:   roleName,
+			UserID:        userID,
+			PrincipalType: principalType,
+			Pending:       pending,
+		})
+	}
+
+	return grants, nil
+}
+
+// snapshotMemberships reads every org, space and team membership in the organization.
+func (d *Connector) snapshotMemberships(ctx context.Context) (map[string]membershipSnapshot, error) {
+	rv := make(map[string]membershipSnapshot)
+	orgID := d.client.OrgID()
+
+	var offset int
+	for {
+		res, err := d.client.ListOrganizationMemberships(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list org memberships: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, orgMembership := range res.Items {
+			rv["organization_membership:"+orgMembership.Sys.ID] = membershipSnapshot{
+				membershipState: membershipState{
+					Version: orgMembership.Sys.Version,
+					Grants: []eventGrant{{
+						ResourceType: orgResourceType.Id,
+						ResourceID:   orgID,
+						Entitlement:  orgMembership.Role,
+						UserID:       orgMembership.Sys.User.Sys.ID,
+					}},
+				},
+				updatedAt: orgMembership.Sys.UpdatedAt,
+			}
+		}
+
+		offset += len(res.Items)
+	}
+
+	spaces := newSpaceBuilder(d.client, nil, d.maxConcurrency, d.directory.getRecent, nil, nil)
+	offset = 0
+	for {
+		res, err := d.client.ListOrganizationSpaceMemberships(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list space memberships: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, spaceMembership := range res.Items {
+			grants, err := spaceMembershipGrants(ctx, spaces, spaceMembership)
+			if err != nil {
+				return nil, err
+			}
+
+			rv["space_membership:"+spaceMembership.Sys.ID] = membershipSnapshot{
+				membershipState: membershipState{
+					Version: spaceMembership.Sys.Version,
+					Grants:  grants,
+				},
+				updatedAt: spaceMembership.Sys.UpdatedAt,
+			}
+		}
+
+		offset += len(res.Items)
+	}
+
+	offset = 0
+	for {
+		res, err := d.client.ListTeamMemberships(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list team memberships: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, membership := range res.Items {
+			rv["team_membership:"+membership.Sys.ID] = membershipSnapshot{
+				membershipState: membershipState{
+					Version: membership.Sys.Version,
+					Grants: []eventGrant{{
+						ResourceType: teamResourceType.Id,
+						ResourceID:   membership.Sys.Team.Sys.ID,
+						Entitlement:  teamMembership,
+						UserID:       membership.Sys.User.Sys.ID,
+					}},
+				},
+				updatedAt: membership.Sys.UpdatedAt,
+			}
+		}
+
+		offset += len(res.Items)
+	}
+
+	return rv, nil
+}
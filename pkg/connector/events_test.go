@@ -0,0 +1,185 @@
+package connector
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func spaceGrant(userID, role string) eventGrant {
+	return eventGrant{ResourceType: spaceResourceType.Id, ResourceID: "space-1", Entitlement: role, UserID: userID}
+}
+
+func snapshotOf(version int, updatedAt time.Time, grants ...eventGrant) membershipSnapshot {
+	return membershipSnapshot{membershipState: membershipState{Version: version, Grants: grants}, updatedAt: updatedAt}
+}
+
+// eventString is the kind, entitlement and principal of an event.
+func eventString(event *v2.Event) string {
+	switch e := event.Event.(type) {
+	case *v2.Event_GrantEvent:
+		return "grant " + e.GrantEvent.Grant.Entitlement.Id + " " + e.GrantEvent.Grant.Principal.Id.Resource
+	case *v2.Event_RevokeEvent:
+		return "revoke " + e.RevokeEvent.Entitlement.Id + " " + e.RevokeEvent.Principal.Id.Resource
+	default:
+		return "unknown"
+	}
+}
+
+func TestDiffMemberships(t *testing.T) {
+	watermark := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	before, after := watermark.Add(-time.Hour), watermark.Add(time.Hour)
+	now := watermark.Add(2 * time.Hour)
+
+	tests := []struct {
+		name      string
+		prev      map[string]membershipState
+		watermark time.Time
+		current   map[string]membershipSnapshot
+		since     time.Time
+		want      []string
+	}{
+		{
+			name:    "first call",
+			current: map[string]membershipSnapshot{"space_membership:sm-1": snapshotOf(1, before, spaceGrant("user-1", "Editor"))},
+		},
+		{
+			name: "first call with an earliest event",
+			current: map[string]membershipSnapshot{
+				"space_membership:sm-1": snapshotOf(1, before, spaceGrant("user-1", "Editor")),
+				"space_membership:sm-2": snapshotOf(1, after, spaceGrant("user-2", "Editor")),
+			},
+			since: watermark,
+			want:  []string{"grant space:space-1:Editor user-2"},
+		},
+		{
+			name: "unchanged",
+			prev: map[string]membershipState{"space_membership:sm-1": {Version: 1, Grants: []eventGrant{spaceGrant("user-1", "Editor")}}},
+			current: map[string]membershipSnapshot{
+				"space_membership:sm-1": snapshotOf(1, before, spaceGrant("user-1", "Editor")),
+			},
+			watermark: watermark,
+		},
+		{
+			name: "added",
+			prev: map[string]membershipState{},
+			current: map[string]membershipSnapshot{
+				"space_membership:sm-1": snapshotOf(1, after, spaceGrant("user-1", "Editor"), spaceGrant("user-1", "Translator")),
+			},
+			watermark: watermark,
+			want:      []string{"grant space:space-1:Editor user-1", "grant space:space-1:Translator user-1"},
+		},
+		{
+			name: "role changed",
+			prev: map[string]membershipState{"space_membership:sm-1": {Version: 1, Grants: []eventGrant{spaceGrant("user-1", "Editor")}}},
+			current: map[string]membershipSnapshot{
+				"space_membership:sm-1": snapshotOf(2, after, spaceGrant("user-1", "Translator")),
+			},
+			watermark: watermark,
+			want:      []string{"grant space:space-1:Translator user-1", "revoke space:space-1:Editor user-1"},
+		},
+		{
+			name: "deleted while another was added",
+			prev: map[string]membershipState{"space_membership:sm-1": {Version: 1, Grants: []eventGrant{spaceGrant("user-1", "Editor")}}},
+			current: map[string]membershipSnapshot{
+				"space_membership:sm-2": snapshotOf(1, after, spaceGrant("user-2", "Editor")),
+			},
+			watermark: watermark,
+			want:      []string{"grant space:space-1:Editor user-2", "revoke space:space-1:Editor user-1"},
+		},
+		{
+			name: "previous memberships unknown",
+			current: map[string]membershipSnapshot{
+				"space_membership:sm-1": snapshotOf(3, before, spaceGrant("user-1", "Editor")),
+				"space_membership:sm-2": snapshotOf(1, after, spaceGrant("user-2", "Editor")),
+			},
+			watermark: watermark,
+			want:      []string{"grant space:space-1:Editor user-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, event := range diffMemberships(tt.prev, tt.watermark, tt.current, tt.since, now) {
+				got = append(got, eventString(event))
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListEventsKeepsTheSnapshotOutOfTheCursor(t *testing.T) {
+	for _, stateDir := range []string{"", t.TempDir()} {
+		s := newFakeContentful(t)
+		c := s.Client(t)
+		d := &Connector{client: c, state: newStateStore(stateDir), directory: newUserDirectory(c)}
+
+		events, state, _, err := d.ListEvents(context.Background(), nil, &pagination.StreamToken{})
+		require.NoError(t, err)
+		require.Empty(t, events)
+
+		data, err := base64.RawURLEncoding.DecodeString(state.Cursor)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "user-ada")
+
+		cursor, err := decodeEventFeedState(state.Cursor)
+		require.NoError(t, err)
+		prev, err := d.loadEventSnapshot(cursor)
+		require.NoError(t, err)
+		require.Contains(t, prev, "organization_membership:om-ada")
+		require.Contains(t, prev, "space_membership:sm-grace")
+		require.Contains(t, prev, "team_membership:tm-linus")
+
+		// a cursor of another call doesn't match the snapshot
+		prev, err = d.loadEventSnapshot(&eventFeedState{Watermark: time.Now()})
+		require.NoError(t, err)
+		require.Nil(t, prev)
+	}
+}
+
+func TestEventFeedGrantsSpaceRolesToInvitations(t *testing.T) {
+	s := newFakeContentful(t)
+	c := s.Client(t)
+	d := &Connector{client: c, directory: newUserDirectory(c)}
+
+	snapshot, err := d.snapshotMemberships(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []eventGrant{{
+		ResourceType:  spaceResourceType.Id,
+		ResourceID:    "space-blog",
+		Entitlement:   "Editor",
+		UserID:        "user-invitee",
+		PrincipalType: invitationResourceType.Id,
+		Pending:       true,
+	}}, snapshot["space_membership:sm-invitee"].Grants)
+}
+
+func TestWebhookEventsGrantSpaceRolesToInvitations(t *testing.T) {
+	s := newFakeContentful(t)
+	c := s.Client(t)
+	d := &Connector{client: c, directory: newUserDirectory(c)}
+
+	body, err := json.Marshal(s.SpaceMemberships[slices.IndexFunc(s.SpaceMemberships, func(m client.SpaceMembership) bool {
+		return m.Sys.ID == "sm-invitee"
+	})])
+	require.NoError(t, err)
+	events, err := d.WebhookEvents(context.Background(), topicSpaceMembershipSave, body)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	g := events[0].GetGrantEvent().GetGrant()
+	require.Equal(t, "space:space-blog:Editor invitation:user-invitee", grantString(g))
+	annos := annotations.Annotations(g.Annotations)
+	require.True(t, annos.Contains(&contentfulv1.PendingInvitation{}))
+}
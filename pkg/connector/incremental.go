@@ -0,0 +1,231 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// fullListingInterval is how long incremental syncs trust the memberships they merged, after that every
+// membership is listed again to drop deletions the counts missed.
+const fullListingInterval = 24 * time.Hour
+
+// syncState is what the previous sync saw of a resource's memberships.
+// Watermark is the latest sys.updatedAt among them, the next sync only fetches memberships updated since.
+// ListedAt is when every membership was last listed.
+type syncState[T any] struct {
+	Watermark   time.Time    `json:"watermark"`
+	ListedAt    time.Time    `json:"listedAt"`
+	Memberships map[string]T `json:"memberships"`
+}
+
+// stateStore keeps one membership state file per resource in a directory.
+// A nil store disables incremental syncs.
+type stateStore struct {
+	dir string
+}
+
+func newStateStore(dir string) *stateStore {
+	if dir == "" {
+		return nil
+	}
+
+	return &stateStore{
+		dir: dir,
+	}
+}
+
+func (s *stateStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+func loadSyncState[T any](s *stateStore, key string) (*syncState[T], error) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("baton-contentful: failed to read membership state %s: %w", key, err)
+	}
+
+	var state syncState[T]
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: invalid membership state %s: %w", key, err)
+	}
+	if state.Memberships == nil {
+		state.Memberships = make(map[string]T)
+	}
+
+	return &state, nil
+}
+
+func saveSyncState[T any](s *stateStore, key string, state *syncState[T]) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("baton-contentful: failed to marshal membership state %s: %w", key, err)
+	}
+
+	err = os.MkdirAll(s.dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("baton-contentful: failed to create membership state directory: %w", err)
+	}
+
+	// write then rename so an interrupted sync never leaves a truncated state file behind
+	tmp := s.path(key) + ".tmp"
+	err = os.WriteFile(tmp, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("baton-contentful: failed to write membership state %s: %w", key, err)
+	}
+
+	err = os.Rename(tmp, s.path(key))
+	if err != nil {
+		return fmt.Errorf("baton-contentful: failed to write membership state %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// membershipSource lists the memberships of a resource for an incremental sync.
+type membershipSource[T any] struct {
+	// list returns a page of memberships, only the ones updated at or after since unless it is zero.
+	list func(ctx context.Context, since time.Time, offset int) ([]T, error)
+	// count returns the current number of memberships.
+	count func(ctx context.Context) (int, error)
+	// sys returns the ID and last update of a membership.
+	sys func(membership T) (string, time.Time)
+}
+
+func (m membershipSource[T]) listAll(ctx context.Context, since time.Time) ([]T, error) {
+	var rv []T
+	var offset int
+	for {
+		items, err := m.list(ctx, since, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) == 0 {
+			break
+		}
+
+		rv = append(rv, items...)
+		offset += len(items)
+	}
+
+	return rv, nil
+}
+
+// syncMemberships returns the current memberships of a resource, ordered by ID.
+// With the state of the previous sync only the memberships updated since are fetched and merged in.
+// Deletions don't show up in that listing, so when the merged memberships don't add up to the current
+// count every membership is listed again. A membership added after the listing and before the count can hide
+// a deletion, so every membership is also listed again once the last full listing is fullListingInterval old.
+func syncMemberships[T any](ctx context.Context, store *stateStore, key string, source membershipSource[T]) ([]T, error) {
+	l := ctxzap.Extract(ctx)
+
+	state, err := loadSyncState[T](store, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if state != nil && time.Since(state.ListedAt) >= fullListingInterval {
+		l.Debug("baton-contentful: memberships were last listed too long ago, listing all of them",
+			zap.String("key", key),
+			zap.Time("listed_at", state.ListedAt),
+		)
+		state = nil
+	}
+
+	if state != nil {
+		updated, err := source.listAll(ctx, state.Watermark)
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := source.count(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, membership := range updated {
+			id, _ := source.sys(membership)
+			state.Memberships[id] = membership
+		}
+
+		if len(state.Memberships) == count {
+			l.Debug("baton-contentful: synced memberships incrementally",
+				zap.String("key", key),
+				zap.Int("updated", len(updated)),
+				zap.Int("total", count),
+			)
+			err = saveSyncState(store, key, state.withWatermark(source))
+			if err != nil {
+				return nil, err
+			}
+			return state.sorted(), nil
+		}
+
+		l.Debug("baton-contentful: memberships were deleted since the previous sync, listing all of them",
+			zap.String("key", key),
+			zap.Int("previous", len(state.Memberships)),
+			zap.Int("total", count),
+		)
+	}
+
+	listedAt := time.Now()
+	all, err := source.listAll(ctx, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	state = &syncState[T]{
+		ListedAt:    listedAt,
+		Memberships: make(map[string]T, len(all)),
+	}
+	for _, membership := range all {
+		id, _ := source.sys(membership)
+		state.Memberships[id] = membership
+	}
+
+	err = saveSyncState(store, key, state.withWatermark(source))
+	if err != nil {
+		return nil, err
+	}
+
+	return state.sorted(), nil
+}
+
+func (s *syncState[T]) withWatermark(source membershipSource[T]) *syncState[T] {
+	for _, membership := range s.Memberships {
+		_, updatedAt := source.sys(membership)
+		if updatedAt.After(s.Watermark) {
+			s.Watermark = updatedAt
+		}
+	}
+
+	return s
+}
+
+func (s *syncState[T]) sorted() []T {
+	ids := make([]string, 0, len(s.Memberships))
+	for id := range s.Memberships {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rv := make([]T, 0, len(ids))
+	for _, id := range ids {
+		rv = append(rv, s.Memberships[id])
+	}
+
+	return rv
+}
@@ -0,0 +1,136 @@
+package connector
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMembership struct {
+	ID        string
+	UpdatedAt time.Time
+}
+
+// fakeMemberships is a resource's memberships, listed two per page.
+type fakeMemberships struct {
+	items map[string]time.Time
+	// number of listings of every membership
+	fullListings int
+	// called before counting the memberships
+	beforeCount func()
+}
+
+func (f *fakeMemberships) source() membershipSource[fakeMembership] {
+	return membershipSource[fakeMembership]{
+		list: func(ctx context.Context, since time.Time, offset int) ([]fakeMembership, error) {
+			if since.IsZero() && offset == 0 {
+				f.fullListings++
+			}
+			var rv []fakeMembership
+			for id, updatedAt := range f.items {
+				if since.IsZero() || !updatedAt.Before(since) {
+					rv = append(rv, fakeMembership{ID: id, UpdatedAt: updatedAt})
+				}
+			}
+			sort.Slice(rv, func(i, j int) bool { return rv[i].ID < rv[j].ID })
+			return rv[min(offset, len(rv)):min(offset+2, len(rv))], nil
+		},
+		count: func(ctx context.Context) (int, error) {
+			if f.beforeCount != nil {
+				f.beforeCount()
+				f.beforeCount = nil
+			}
+			return len(f.items), nil
+		},
+		sys: func(membership fakeMembership) (string, time.Time) {
+			return membership.ID, membership.UpdatedAt
+		},
+	}
+}
+
+func membershipIDs(memberships []fakeMembership) []string {
+	rv := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		rv = append(rv, membership.ID)
+	}
+	return rv
+}
+
+func TestSyncMembershipsMergesUpdates(t *testing.T) {
+	ctx := context.Background()
+	store := newStateStore(t.TempDir())
+	t0 := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	f := &fakeMemberships{items: map[string]time.Time{"m-1": t0, "m-2": t0, "m-3": t0.Add(time.Hour)}}
+
+	memberships, err := syncMemberships(ctx, store, "memberships", f.source())
+	require.NoError(t, err)
+	require.Equal(t, []string{"m-1", "m-2", "m-3"}, membershipIDs(memberships))
+	require.Equal(t, 1, f.fullListings)
+
+	f.items["m-2"] = t0.Add(2 * time.Hour)
+	f.items["m-4"] = t0.Add(3 * time.Hour)
+
+	memberships, err = syncMemberships(ctx, store, "memberships", f.source())
+	require.NoError(t, err)
+	require.Equal(t, []string{"m-1", "m-2", "m-3", "m-4"}, membershipIDs(memberships))
+	require.Equal(t, t0.Add(2*time.Hour), memberships[1].UpdatedAt)
+	require.Equal(t, 1, f.fullListings)
+
+	state, err := loadSyncState[fakeMembership](store, "memberships")
+	require.NoError(t, err)
+	require.Equal(t, t0.Add(3*time.Hour), state.Watermark)
+}
+
+func TestSyncMembershipsDeletions(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("deleted", func(t *testing.T) {
+		store := newStateStore(t.TempDir())
+		f := &fakeMemberships{items: map[string]time.Time{"m-1": t0, "m-2": t0, "m-3": t0}}
+		_, err := syncMemberships(ctx, store, "memberships", f.source())
+		require.NoError(t, err)
+
+		delete(f.items, "m-2")
+
+		memberships, err := syncMemberships(ctx, store, "memberships", f.source())
+		require.NoError(t, err)
+		require.Equal(t, []string{"m-1", "m-3"}, membershipIDs(memberships))
+		require.Equal(t, 2, f.fullListings)
+	})
+
+	t.Run("deleted while another was added during the sync", func(t *testing.T) {
+		store := newStateStore(t.TempDir())
+		f := &fakeMemberships{items: map[string]time.Time{"m-1": t0, "m-2": t0}}
+		_, err := syncMemberships(ctx, store, "memberships", f.source())
+		require.NoError(t, err)
+
+		delete(f.items, "m-1")
+		f.beforeCount = func() {
+			f.items["m-3"] = t0.Add(time.Hour)
+		}
+
+		// the counts add up, the deletion goes unnoticed until the next full listing
+		memberships, err := syncMemberships(ctx, store, "memberships", f.source())
+		require.NoError(t, err)
+		require.Equal(t, []string{"m-1", "m-2"}, membershipIDs(memberships))
+		require.Equal(t, 1, f.fullListings)
+
+		state, err := loadSyncState[fakeMembership](store, "memberships")
+		require.NoError(t, err)
+		state.ListedAt = time.Now().Add(-fullListingInterval)
+		require.NoError(t, saveSyncState(store, "memberships", state))
+
+		memberships, err = syncMemberships(ctx, store, "memberships", f.source())
+		require.NoError(t, err)
+		require.Equal(t, []string{"m-2", "m-3"}, membershipIDs(memberships))
+		require.Equal(t, 2, f.fullListings)
+
+		state, err = loadSyncState[fakeMembership](store, "memberships")
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), state.ListedAt, time.Minute)
+	})
+}
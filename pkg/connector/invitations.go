@@ -0,0 +1,190 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const pendingInvitationWarning = "membership of a user who hasn't accepted their org invitation yet"
+
+// directoryMaxAge is how old the directory the event feed and webhook events tell invitees apart with can be.
+const directoryMaxAge = 5 * time.Minute
+
+// userDirectory tells the users the user builder lists apart from invitees who haven't accepted
+// their org invitation yet, which memberships can already point at.
+type userDirectory struct {
+	// userID: listed by the user builder
+	users map[string]bool
+	// userID: pending org membership
+	pending map[string]client.OrganizationMembership
+}
+
+// invitees returns the pending org memberships of users the user builder doesn't list.
+func (d *userDirectory) invitees() []client.OrganizationMembership {
+	userIDs := make([]string, 0, len(d.pending))
+	for userID := range d.pending {
+		if !d.users[userID] {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Strings(userIDs)
+
+	rv := make([]client.OrganizationMembership, 0, len(userIDs))
+	for _, userID := range userIDs {
+		rv = append(rv, d.pending[userID])
+	}
+	return rv
+}
+
+// principal returns the principal of a membership of the user: the invitation when the user hasn't accepted it
+// and isn't listed as a user yet, the user otherwise. The membership is pending as long as the invitation is.
+func (d *userDirectory) principal(userID string) (*v2.ResourceId, bool, error) {
+	_, pending := d.pending[userID]
+	if pending && !d.users[userID] {
+		principalID, err := resourceSdk.NewResourceID(invitationResourceType, userID)
+		return principalID, true, err
+	}
+
+	principalID, err := resourceSdk.NewResourceID(userResourceType, userID)
+	return principalID, pending, err
+}
+
+func listUserDirectory(ctx context.Context, c *client.Client) (*userDirectory, error) {
+	rv := &userDirectory{
+		users:   make(map[string]bool),
+		pending: make(map[string]client.OrganizationMembership),
+	}
+
+	var offset int
+	for {
+		res, err := c.ListUsers(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list users: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, user := range res.Items {
+			rv.users[user.Sys.ID] = true
+		}
+
+		offset += len(res.Items)
+	}
+
+	offset = 0
+	for {
+		res, err := c.ListOrganizationMemberships(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list org memberships: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, orgMembership := range res.Items {
+			if orgMembership.Sys.Status == orgMembershipPending {
+				rv.pending[orgMembership.Sys.User.Sys.ID] = orgMembership
+			}
+		}
+
+		offset += len(res.Items)
+	}
+
+	return rv, nil
+}
+
+// sharedDirectory is the directory of the client's organization, shared by the builders. It is listed once per sync,
+// the invitation builder lists it again when a sync starts listing invitations.
+type sharedDirectory struct {
+	client *client.Client
+	cache  *keyedCache[*userDirectory]
+}
+
+func newUserDirectory(c *client.Client) *sharedDirectory {
+	return &sharedDirectory{
+		client: c,
+		cache: newKeyedCache(0, func(ctx context.Context, _ string) (*userDirectory, error) {
+			return listUserDirectory(ctx, c)
+		}),
+	}
+}
+
+func (d *sharedDirectory) get(ctx context.Context) (*userDirectory, error) {
+	return d.cache.get(ctx, d.client.OrgID())
+}
+
+// getRecent lists the directory again unless it is less than directoryMaxAge old, for the event feed and webhook
+// events, which see membership changes between syncs.
+func (d *sharedDirectory) getRecent(ctx context.Context) (*userDirectory, error) {
+	return d.cache.refresh(ctx, d.client.OrgID(), directoryMaxAge)
+}
+
+// invitationBuilder lists the invitees that memberships point at before they have accepted
+// their invitation, so those grants have a principal.
+type invitationBuilder struct {
+	directory *sharedDirectory
+}
+
+func (o *invitationBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return invitationResourceType
+}
+
+// invitationResource is keyed by the invitee's user ID, the ID memberships link to.
+func invitationResource(orgMembership client.OrganizationMembership) (*v2.Resource, error) {
+	userID := orgMembership.Sys.User.Sys.ID
+	return resourceSdk.NewResource(
+		fmt.Sprintf("Pending invitation %s", userID),
+		invitationResourceType,
+		userID,
+		resourceSdk.WithDescription(fmt.Sprintf("Invited as %s on %s, not accepted yet", orgMembership.Role, orgMembership.Sys.CreatedAt.Format("2006-01-02"))),
+	)
+}
+
+// List returns every invitee in a single page. Invitations are listed once per sync, before any grant is, so the
+// directory is listed again: invitees who accepted since the previous sync are users now.
+func (o *invitationBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	o.directory.cache.reset()
+	directory, err := o.directory.get(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	invitees := directory.invitees()
+	rv := make([]*v2.Resource, 0, len(invitees))
+	for _, orgMembership := range invitees {
+		r, err := invitationResource(orgMembership)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create invitation resource %s: %w", orgMembership.Sys.ID, err)
+		}
+		rv = append(rv, r)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements always returns an empty slice for invitations.
+func (o *invitationBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for invitations since they don't have any entitlements.
+func (o *invitationBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newInvitationBuilder(directory *sharedDirectory) *invitationBuilder {
+	return &invitationBuilder{
+		directory: directory,
+	}
+}
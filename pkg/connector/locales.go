@@ -0,0 +1,151 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const localeEdit = "edit"
+
+// localeBuilder lists the locales of environments, keyed by their code. Their edit entitlement is granted through the space roles
+// whose policies restrict editing to some locales, the translator roles.
+type localeBuilder struct {
+	client *client.Client
+	spaces *spaceBuilder
+}
+
+func (o *localeBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return localeResourceType
+}
+
+func localeResource(parentResourceID *v2.ResourceId, spaceID, environmentID string, locale client.Locale) (*v2.Resource, error) {
+	description := locale.Code
+	if locale.Default {
+		description += ", default locale"
+	}
+	if locale.FallbackCode != "" {
+		description += fmt.Sprintf(", falls back to %s", locale.FallbackCode)
+	}
+
+	return resourceSdk.NewResource(
+		locale.Name,
+		localeResourceType,
+		environmentChildResourceID(spaceID, environmentID, locale.Code),
+		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithDescription(description),
+	)
+}
+
+func (o *localeBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// locales are only listed under their environment
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+	spaceID, environmentID, err := splitEnvironmentResourceID(parentResourceID.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var offset int
+	if pToken.Token != "" {
+		offset, err = strconv.Atoi(pToken.Token)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	res, err := o.client.ListLocales(ctx, spaceID, environmentID, offset)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list locales of environment %s: %w", parentResourceID.Resource, err)
+	}
+
+	if len(res.Items) == 0 {
+		return nil, "", nil, nil
+	}
+	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
+
+	rv := make([]*v2.Resource, 0, len(res.Items))
+	for _, locale := range res.Items {
+		r, err := localeResource(parentResourceID, spaceID, environmentID, locale)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create locale resource %s: %w", locale.Code, err)
+		}
+		rv = append(rv, r)
+	}
+
+	return rv, nextOffset, nil, nil
+}
+
+func (o *localeBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			localeEdit,
+			entitlement.WithGrantableTo(spaceRolesGrantableTo...),
+			entitlement.WithDescription(fmt.Sprintf("Edit %s content, through a space role restricted to some locales", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Edit %s content", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// localeEditors returns the entitlements of the space roles that name locales in their policies and can edit the locale.
+func localeEditors(spaceID, code string, roles []client.Role) ([]string, error) {
+	var rv []string
+	for _, role := range roles {
+		if len(roleLocales(role)) == 0 {
+			continue
+		}
+		if !roleAllows(role, "update", policyDoc{policyLocale: code}) {
+			continue
+		}
+
+		entitlementID, err := spaceRoleEntitlementID(spaceID, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, entitlementID)
+	}
+	return rv, nil
+}
+
+// Grants grants the edit entitlement to the space, expanded to the members of the space roles that can edit the locale.
+func (o *localeBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	spaceID, _, code, err := splitEnvironmentChildResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	roles, err := o.spaces.roles.get(ctx, spaceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	editors, err := localeEditors(spaceID, code, roles)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if len(editors) == 0 {
+		return nil, "", nil, nil
+	}
+
+	g, err := spaceRolesGrant(resource, localeEdit, spaceID, editors)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return []*v2.Grant{g}, "", nil, nil
+}
+
+func newLocaleBuilder(client *client.Client, spaces *spaceBuilder) *localeBuilder {
+	return &localeBuilder{
+		client: client,
+		spaces: spaces,
+	}
+}
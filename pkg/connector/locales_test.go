@@ -0,0 +1,51 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleEditors(t *testing.T) {
+	roles := []client.Role{
+		{
+			Name: "Editor",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": "all", "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+			},
+		},
+		{
+			Name: "Translator DE",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": ["read"], "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+				decodePolicy(t, `{"effect": "allow", "actions": ["update"], "constraint": {"and": [
+					{"equals": [{"doc": "sys.type"}, "Entry"]},
+					{"paths": [{"doc": "fields.%.de-DE"}]}
+				]}}`),
+			},
+		},
+		{
+			Name: "Translator all but FR",
+			Policies: []client.Policy{
+				decodePolicy(t, `{"effect": "allow", "actions": ["update"], "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+				decodePolicy(t, `{"effect": "deny", "actions": ["update"], "constraint": {"paths": [{"doc": "fields.%.fr-FR"}]}}`),
+			},
+		},
+	}
+
+	require.Equal(t, []string{"de-DE"}, roleLocales(roles[1]))
+	require.Empty(t, roleLocales(roles[0]))
+
+	editors, err := localeEditors("space-1", "de-DE", roles)
+	require.NoError(t, err)
+	require.Equal(t, []string{"space:space-1:Translator DE", "space:space-1:Translator all but FR"}, editors)
+
+	editors, err = localeEditors("space-1", "fr-FR", roles)
+	require.NoError(t, err)
+	require.Empty(t, editors)
+
+	editors, err = localeEditors("space-1", "en-US", roles)
+	require.NoError(t, err)
+	require.Equal(t, []string{"space:space-1:Translator all but FR"}, editors)
+}
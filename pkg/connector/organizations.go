@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
 	"github.com/conductorone/baton-contentful/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -12,6 +14,8 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -21,20 +25,80 @@ const (
 	orgMember    = "member"
 )
 
+// orgRoles are the roles an organization membership can have, from least to most privileged.
+var orgRoles = []string{orgMember, orgDeveloper, orgAdmin, orgOwner}
+
+const privilegedWithoutMFAWarning = "privileged organization role held by a user without 2FA enabled"
+
+// mfaCacheTTL is how long the 2FA status of the users is cached before they are listed again.
+const mfaCacheTTL = 10 * time.Minute
+
 type orgBuilder struct {
-	client *client.Client
+	client                   *client.Client
+	flagPrivilegedWithoutMFA bool
+	// nil unless grants are synced incrementally
+	state *stateStore
+	// nil when entitlements aren't classified by risk
+	risk riskRules
+	// orgID: userID: 2faEnabled
+	mfa *keyedCache[map[string]bool]
+}
+
+// listMFAEnabled lists whether each user of the organization has 2FA enabled.
+func (o *orgBuilder) listMFAEnabled(ctx context.Context, orgID string) (map[string]bool, error) {
+	rv := make(map[string]bool)
+	var offset int
+	for {
+		res, err := o.client.ListUsers(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list users: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, user := range res.Items {
+			rv[user.Sys.ID] = user.TwoFAEnabled
+		}
+
+		offset += len(res.Items)
+	}
+	return rv, nil
 }
 
 func (o *orgBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 	return orgResourceType
 }
 
-func orgResource(org client.Organization) *v2.Resource {
+// orgResource includes the usage in the profile unless it is nil.
+func orgResource(org client.Organization, idp *client.IdentityProvider, usage map[string]interface{}) *v2.Resource {
+	profile := map[string]interface{}{
+		"ssoEnabled": false,
+	}
+	for k, v := range usage {
+		profile[k] = v
+	}
+
+	if idp != nil {
+		profile["ssoEnabled"] = idp.Enabled
+		profile["ssoRestrictedMode"] = idp.RestrictedMode
+		profile["ssoName"] = idp.SSOName
+		profile["idpName"] = idp.IdpName
+		profile["idpSsoTargetUrl"] = idp.IdpSSOTargetURL
+		if idp.TestConnectionAt != nil {
+			profile["idpTestConnectionAt"] = idp.TestConnectionAt.Format(time.RFC3339)
+			profile["idpTestConnectionResult"] = idp.TestConnectionResult
+		}
+	}
+
 	orgResource, err := resourceSdk.NewGroupResource(
 		org.Name,
 		orgResourceType,
 		org.Sys.ID,
-		[]resourceSdk.GroupTraitOption{},
+		[]resourceSdk.GroupTraitOption{
+			resourceSdk.WithGroupProfile(profile),
+		},
 	)
 	if err != nil {
 		return nil
@@ -67,47 +131,76 @@ func (o *orgBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 
 	rv := []*v2.Resource{}
 	for _, org := range res.Items {
-		rv = append(rv, orgResource(org))
+		idp, err := o.getIdentityProvider(ctx, org.Sys.ID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to get identity provider for organization %s: %w", org.Sys.ID, err)
+		}
+
+		// usage is only known for the organization the connector is configured with
+		var usage map[string]interface{}
+		if org.Sys.ID == o.client.OrgID() {
+			usage, err = orgUsage(ctx, o.client)
+			if err != nil {
+				return nil, "", nil, err
+			}
+		}
+		rv = append(rv, orgResource(org, idp, usage))
 	}
 
 	return rv, nextOffset, nil, nil
 }
 
+// getIdentityProvider returns nil when the organization has no SSO configured
+// or the token is not allowed to read its configuration.
+func (o *orgBuilder) getIdentityProvider(ctx context.Context, orgID string) (*client.IdentityProvider, error) {
+	idp, err := o.client.GetIdentityProvider(ctx, orgID)
+	switch status.Code(err) {
+	case codes.OK:
+		return idp, nil
+	case codes.NotFound, codes.PermissionDenied:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
 func (o *orgBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
-	// owner, admin, developer, member
-	return []*v2.Entitlement{
-		entitlement.NewAssignmentEntitlement(
-			resource,
-			orgOwner,
-			entitlement.WithGrantableTo(userResourceType),
-			entitlement.WithDescription(fmt.Sprintf("Owner of the %s organization", resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Owner of the %s organization", resource.DisplayName)),
-		),
-		entitlement.NewAssignmentEntitlement(
-			resource,
-			orgAdmin,
-			entitlement.WithGrantableTo(userResourceType),
-			entitlement.WithDescription(fmt.Sprintf("Admin of the %s organization", resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Admin of the %s organization", resource.DisplayName)),
-		),
-		entitlement.NewAssignmentEntitlement(
-			resource,
-			orgDeveloper,
+	roles := []struct {
+		name  string
+		title string
+	}{
+		{orgOwner, "Owner"},
+		{orgAdmin, "Admin"},
+		{orgDeveloper, "Developer"},
+		{orgMember, "Member"},
+	}
+
+	rv := make([]*v2.Entitlement, 0, len(roles))
+	for _, role := range roles {
+		entitlementOpts := []entitlement.EntitlementOption{
 			entitlement.WithGrantableTo(userResourceType),
-			entitlement.WithDescription(fmt.Sprintf("Developer of the %s organization", resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Developer of the %s organization", resource.DisplayName)),
-		),
-		entitlement.NewAssignmentEntitlement(
+			entitlement.WithDescription(fmt.Sprintf("%s of the %s organization", role.title, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s of the %s organization", role.title, resource.DisplayName)),
+		}
+		if rule := o.risk.classifyOrgRole(role.name); rule != nil {
+			entitlementOpts = append(entitlementOpts, entitlement.WithAnnotation(riskAnnotation(rule)))
+		}
+
+		rv = append(rv, entitlement.NewAssignmentEntitlement(
 			resource,
-			orgMember,
-			entitlement.WithGrantableTo(userResourceType),
-			entitlement.WithDescription(fmt.Sprintf("Member of the %s organization", resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Member of the %s organization", resource.DisplayName)),
-		),
-	}, "", nil, nil
+			role.name,
+			entitlementOpts...,
+		))
+	}
+	return rv, "", nil, nil
 }
 
 func (o *orgBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	if o.state != nil {
+		rv, err := o.incrementalGrants(ctx, resource)
+		return rv, "", nil, err
+	}
+
 	var offset int
 	var err error
 	if pToken.Token != "" {
@@ -129,19 +222,86 @@ func (o *orgBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *
 
 	rv := []*v2.Grant{}
 	for _, orgMembership := range res.Items {
-		principalID, err := resourceSdk.NewResourceID(userResourceType, orgMembership.Sys.User.Sys.ID)
+		g, err := o.membershipGrant(ctx, resource, orgMembership)
 		if err != nil {
-			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", orgMembership.Sys.User.Sys.ID, err)
+			return nil, "", nil, err
 		}
-		rv = append(rv, grant.NewGrant(
-			resource,
-			orgMembership.Role,
-			principalID,
-		))
+		rv = append(rv, g)
 	}
 	return rv, nextOffset, nil, nil
 }
 
+// incrementalGrants returns every grant of the organization in a single page,
+// only the memberships updated since the previous sync are fetched.
+func (o *orgBuilder) incrementalGrants(ctx context.Context, resource *v2.Resource) ([]*v2.Grant, error) {
+	memberships, err := syncMemberships(ctx, o.state, "organization-"+resource.Id.Resource, membershipSource[client.OrganizationMembership]{
+		list: func(ctx context.Context, since time.Time, offset int) ([]client.OrganizationMembership, error) {
+			var res *client.GetOrganizationMembershipsResponse
+			var err error
+			if since.IsZero() {
+				res, err = o.client.ListOrganizationMemberships(ctx, offset)
+			} else {
+				res, err = o.client.ListOrganizationMembershipsUpdatedSince(ctx, since, offset)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("baton-contentful: failed to list org memberships: %w", err)
+			}
+			return res.Items, nil
+		},
+		count: func(ctx context.Context) (int, error) {
+			count, err := o.client.CountOrganizationMemberships(ctx)
+			if err != nil {
+				return 0, fmt.Errorf("baton-contentful: failed to count org memberships: %w", err)
+			}
+			return count, nil
+		},
+		sys: func(orgMembership client.OrganizationMembership) (string, time.Time) {
+			return orgMembership.Sys.ID, orgMembership.Sys.UpdatedAt
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]*v2.Grant, 0, len(memberships))
+	for _, orgMembership := range memberships {
+		g, err := o.membershipGrant(ctx, resource, orgMembership)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, g)
+	}
+	return rv, nil
+}
+
+func (o *orgBuilder) membershipGrant(ctx context.Context, resource *v2.Resource, orgMembership client.OrganizationMembership) (*v2.Grant, error) {
+	userID := orgMembership.Sys.User.Sys.ID
+	principalID, err := resourceSdk.NewResourceID(userResourceType, userID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", userID, err)
+	}
+
+	var grantOpts []grant.GrantOption
+	if o.flagPrivilegedWithoutMFA && (orgMembership.Role == orgOwner || orgMembership.Role == orgAdmin) {
+		mfaEnabled, err := o.mfa.get(ctx, resource.Id.Resource)
+		if err != nil {
+			return nil, err
+		}
+		if enabled, found := mfaEnabled[userID]; found && !enabled {
+			grantOpts = append(grantOpts, grant.WithAnnotation(&contentfulv1.PrivilegedWithoutMFA{
+				Warning: privilegedWithoutMFAWarning,
+			}))
+		}
+	}
+
+	return grant.NewGrant(
+		resource,
+		orgMembership.Role,
+		principalID,
+		grantOpts...,
+	), nil
+}
+
 // can't provision organization membership, it requires creating an account
 // https://www.contentful.com/developers/docs/references/user-management-api/#/reference/organization-memberships
 func (o *orgBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
@@ -168,8 +328,13 @@ func (o *orgBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.A
 	return nil, nil
 }
 
-func newOrgBuilder(client *client.Client) *orgBuilder {
-	return &orgBuilder{
-		client: client,
+func newOrgBuilder(client *client.Client, flagPrivilegedWithoutMFA bool, state *stateStore, risk riskRules) *orgBuilder {
+	o := &orgBuilder{
+		client:                   client,
+		flagPrivilegedWithoutMFA: flagPrivilegedWithoutMFA,
+		state:                    state,
+		risk:                     risk,
 	}
+	o.mfa = newKeyedCache(mfaCacheTTL, o.listMFAEnabled)
+	return o
 }
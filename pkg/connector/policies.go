@@ -0,0 +1,437 @@
+package connector
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+)
+
+const (
+	policyAllow = "allow"
+	policyDeny  = "deny"
+	// policyAllActions is what Actions is instead of a list when a policy applies to every action.
+	policyAllActions = "all"
+	// policyLocale is the policyDoc key of the locale edited, checked against the "paths" of a constraint.
+	policyLocale = "locale"
+)
+
+// policyActions normalizes the actions of a policy, "all" or a list of them, to a sorted list.
+// "all" stays a list of just "all".
+func policyActions(policy client.Policy) []string {
+	return normalizeActions(policy.Actions)
+}
+
+// normalizeActions normalizes actions decoded from "all" or a list of actions, as policies and
+// permissions have them.
+func normalizeActions(v any) []string {
+	var rv []string
+	switch actions := v.(type) {
+	case string:
+		rv = []string{actions}
+	case []string:
+		rv = append(rv, actions...)
+	case []any:
+		for _, action := range actions {
+			if s, ok := action.(string); ok {
+				rv = append(rv, s)
+			}
+		}
+	}
+
+	for _, action := range rv {
+		if action == policyAllActions {
+			return []string{policyAllActions}
+		}
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+func policyHasAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == policyAllActions || a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// constraint is a parsed policy constraint, a tree of and/or/not over comparisons of document paths
+// such as {"equals": [{"doc": "sys.type"}, "Entry"]}.
+type constraint struct {
+	// and, or, not, equals, in, paths or, for anything else, the raw JSON
+	op       string
+	doc      string
+	values   []string
+	children []constraint
+	raw      string
+}
+
+func parseConstraint(c map[string]any) constraint {
+	if len(c) != 1 {
+		// an empty constraint matches everything, several operators in one object are implicitly and-ed
+		if len(c) == 0 {
+			return constraint{op: "and"}
+		}
+		ops := make([]string, 0, len(c))
+		for op := range c {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		rv := constraint{op: "and"}
+		for _, op := range ops {
+			rv.children = append(rv.children, parseConstraint(map[string]any{op: c[op]}))
+		}
+		return rv
+	}
+
+	for op, arg := range c {
+		switch op {
+		case "and", "or":
+			args, ok := arg.([]any)
+			if !ok {
+				break
+			}
+			rv := constraint{op: op}
+			for _, child := range args {
+				childMap, ok := child.(map[string]any)
+				if !ok {
+					return rawConstraint(c)
+				}
+				rv.children = append(rv.children, parseConstraint(childMap))
+			}
+			return rv
+
+		case "not":
+			child, ok := arg.(map[string]any)
+			if !ok {
+				break
+			}
+			return constraint{op: op, children: []constraint{parseConstraint(child)}}
+
+		case "equals", "in":
+			args, ok := arg.([]any)
+			if !ok || len(args) != 2 {
+				break
+			}
+			doc, ok := constraintDoc(args[0])
+			if !ok {
+				break
+			}
+			var values []string
+			if op == "in" {
+				list, ok := args[1].([]any)
+				if !ok {
+					break
+				}
+				for _, v := range list {
+					values = append(values, fmt.Sprint(v))
+				}
+			} else {
+				values = []string{fmt.Sprint(args[1])}
+			}
+			return constraint{op: op, doc: doc, values: values}
+
+		case "paths":
+			args, ok := arg.([]any)
+			if !ok {
+				break
+			}
+			rv := constraint{op: op}
+			for _, path := range args {
+				doc, ok := constraintDoc(path)
+				if !ok {
+					return rawConstraint(c)
+				}
+				rv.values = append(rv.values, doc)
+			}
+			return rv
+		}
+	}
+
+	return rawConstraint(c)
+}
+
+func constraintDoc(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	doc, ok := m["doc"].(string)
+	return doc, ok
+}
+
+func rawConstraint(c map[string]any) constraint {
+	raw, _ := json.Marshal(c)
+	return constraint{op: "raw", raw: string(raw)}
+}
+
+// match is the result of checking a constraint against a policyDoc, which only knows some of the document.
+type match int
+
+const (
+	matchNo match = iota
+	matchPartly
+	matchYes
+)
+
+// policyDoc describes the documents an action is checked against by their values at constraint paths,
+// such as "sys.type" and "sys.contentType.sys.id", and by the locale edited under policyLocale.
+// Paths it leaves out may have any value.
+type policyDoc map[string]string
+
+func (c constraint) matches(doc policyDoc) match {
+	switch c.op {
+	case "and":
+		rv := matchYes
+		for _, child := range c.children {
+			rv = min(rv, child.matches(doc))
+		}
+		return rv
+
+	case "or":
+		if len(c.children) == 0 {
+			return matchYes
+		}
+		rv := matchNo
+		for _, child := range c.children {
+			rv = max(rv, child.matches(doc))
+		}
+		return rv
+
+	case "not":
+		return matchYes - c.children[0].matches(doc)
+
+	case "equals", "in":
+		value, ok := doc[c.doc]
+		if !ok {
+			return matchPartly
+		}
+		for _, v := range c.values {
+			if v == value {
+				return matchYes
+			}
+		}
+		return matchNo
+
+	case "paths":
+		locale, ok := doc[policyLocale]
+		if !ok {
+			return matchPartly
+		}
+		rv := matchNo
+		for _, path := range c.values {
+			field, pathLocale := fieldPath(path)
+			if pathLocale != "%" && pathLocale != locale {
+				continue
+			}
+			// every field of the locale, or only some of them
+			if field == "%" {
+				return matchYes
+			}
+			rv = matchPartly
+		}
+		return rv
+	}
+
+	return matchPartly
+}
+
+// locales returns the locales the paths of the constraint name, leaving out "%".
+func (c constraint) locales() []string {
+	var rv []string
+	if c.op == "paths" {
+		for _, path := range c.values {
+			if _, locale := fieldPath(path); locale != "%" {
+				rv = append(rv, locale)
+			}
+		}
+	}
+	for _, child := range c.children {
+		rv = append(rv, child.locales()...)
+	}
+	return rv
+}
+
+// roleLocales returns the locales the policies of the role name, sorted and without duplicates.
+func roleLocales(role client.Role) []string {
+	var rv []string
+	for _, policy := range role.Policies {
+		rv = append(rv, parseConstraint(policy.Constraint).locales()...)
+	}
+	sort.Strings(rv)
+	return slices.Compact(rv)
+}
+
+// fieldPath splits a "fields.<field>.<locale>" path, "%" standing for any field or locale.
+func fieldPath(path string) (string, string) {
+	parts := strings.Split(strings.TrimPrefix(path, "fields."), ".")
+	field, locale := "%", "%"
+	if len(parts) > 0 && parts[0] != "" {
+		field = parts[0]
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		locale = parts[1]
+	}
+	return field, locale
+}
+
+// roleAllows tells whether the role lets the action be done on at least some of the documents: an allow policy
+// matches them at least partly and no deny policy matches all of them.
+func roleAllows(role client.Role, action string, doc policyDoc) bool {
+	allowed := false
+	for _, policy := range role.Policies {
+		if !policyHasAction(policyActions(policy), action) {
+			continue
+		}
+		m := parseConstraint(policy.Constraint).matches(doc)
+		switch policy.Effect {
+		case policyAllow:
+			allowed = allowed || m != matchNo
+		case policyDeny:
+			if m == matchYes {
+				return false
+			}
+		}
+	}
+	return allowed
+}
+
+// policySummary describes a policy, e.g. "can publish Entries of type blogPost in en-US".
+func policySummary(policy client.Policy) string {
+	verb := "can"
+	if policy.Effect == policyDeny {
+		verb = "cannot"
+	}
+
+	actions := policyActions(policy)
+	what := joinWords(actions, "and")
+	if len(actions) == 1 && actions[0] == policyAllActions {
+		what = "do anything with"
+	}
+	if len(actions) == 0 {
+		what = "do nothing with"
+	}
+
+	noun, qualifiers := describeConstraint(parseConstraint(policy.Constraint))
+	return strings.Join(append([]string{verb, what, noun}, qualifiers...), " ")
+}
+
+// roleSummary describes every policy of the role.
+func roleSummary(role client.Role) string {
+	if len(role.Policies) == 0 {
+		return "no access to content"
+	}
+
+	summaries := make([]string, 0, len(role.Policies))
+	for _, policy := range role.Policies {
+		summaries = append(summaries, policySummary(policy))
+	}
+	return strings.Join(summaries, "; ")
+}
+
+// describeConstraint returns what the constraint applies to, "Entries" or "Assets" when it restricts sys.type,
+// and the rest of its restrictions.
+func describeConstraint(c constraint) (string, []string) {
+	clauses := []constraint{c}
+	if c.op == "and" {
+		clauses = c.children
+	}
+
+	noun := "content"
+	var qualifiers []string
+	for _, clause := range clauses {
+		if (clause.op == "equals" || clause.op == "in") && clause.doc == "sys.type" && noun == "content" {
+			noun = docTypes(clause.values)
+			continue
+		}
+		qualifiers = append(qualifiers, describeClause(clause))
+	}
+	return noun, qualifiers
+}
+
+func describeClause(c constraint) string {
+	switch c.op {
+	case "and", "or":
+		parts := make([]string, 0, len(c.children))
+		for _, child := range c.children {
+			parts = append(parts, describeClause(child))
+		}
+		if len(parts) == 1 {
+			return parts[0]
+		}
+		return "(" + joinWords(parts, c.op) + ")"
+
+	case "not":
+		return "not " + describeClause(c.children[0])
+
+	case "equals", "in":
+		values := joinWords(c.values, "or")
+		switch c.doc {
+		case "sys.type":
+			return "that are " + docTypes(c.values)
+		case "sys.contentType.sys.id":
+			if len(c.values) == 1 {
+				return "of type " + values
+			}
+			return "of types " + values
+		case "sys.id":
+			return "with ID " + values
+		case "sys.createdBy.sys.id":
+			if len(c.values) == 1 && c.values[0] == "User.current()" {
+				return "created by themselves"
+			}
+			return "created by " + values
+		}
+		return fmt.Sprintf("where %s is %s", c.doc, values)
+
+	case "paths":
+		parts := make([]string, 0, len(c.values))
+		for _, path := range c.values {
+			field, locale := fieldPath(path)
+			switch {
+			case field == "%" && locale == "%":
+				parts = append(parts, "in any field")
+			case field == "%":
+				parts = append(parts, "in "+locale)
+			case locale == "%":
+				parts = append(parts, "in field "+field)
+			default:
+				parts = append(parts, fmt.Sprintf("in field %s in %s", field, locale))
+			}
+		}
+		return joinWords(parts, "or")
+	}
+
+	return "matching " + c.raw
+}
+
+func docTypes(types []string) string {
+	nouns := make([]string, 0, len(types))
+	for _, t := range types {
+		switch t {
+		case "Entry":
+			nouns = append(nouns, "Entries")
+		case "Asset":
+			nouns = append(nouns, "Assets")
+		default:
+			nouns = append(nouns, t)
+		}
+	}
+	return joinWords(nouns, "and")
+}
+
+// joinWords joins the words as a list in a sentence, "a, b and c".
+func joinWords(words []string, conjunction string) string {
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + conjunction + " " + words[len(words)-1]
+}
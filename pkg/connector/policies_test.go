@@ -0,0 +1,124 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+// decodePolicy decodes a policy the way it comes from the API.
+func decodePolicy(t *testing.T, s string) client.Policy {
+	t.Helper()
+
+	var rv client.Policy
+	require.NoError(t, json.Unmarshal([]byte(s), &rv))
+	return rv
+}
+
+func TestPolicyActions(t *testing.T) {
+	require.Equal(t, []string{"all"}, policyActions(client.Policy{Actions: "all"}))
+	require.Equal(t, []string{"publish", "read"}, policyActions(client.Policy{Actions: []string{"read", "publish"}}))
+	require.Equal(t, []string{"read"}, policyActions(client.Policy{Actions: []any{"read"}}))
+	require.Equal(t, []string{"all"}, policyActions(client.Policy{Actions: []any{"read", "all"}}))
+	require.Empty(t, policyActions(client.Policy{}))
+}
+
+func TestPolicySummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{
+			name:   "everything on entries",
+			policy: `{"effect": "allow", "actions": "all", "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`,
+			want:   "can do anything with Entries",
+		},
+		{
+			name: "content type and locale",
+			policy: `{"effect": "allow", "actions": ["publish"], "constraint": {"and": [
+				{"equals": [{"doc": "sys.type"}, "Entry"]},
+				{"equals": [{"doc": "sys.contentType.sys.id"}, "blogPost"]},
+				{"paths": [{"doc": "fields.%.en-US"}]}
+			]}}`,
+			want: "can publish Entries of type blogPost in en-US",
+		},
+		{
+			name: "deny on several content types",
+			policy: `{"effect": "deny", "actions": ["delete", "archive"], "constraint": {"and": [
+				{"equals": [{"doc": "sys.type"}, "Entry"]},
+				{"in": [{"doc": "sys.contentType.sys.id"}, ["page", "settings"]]}
+			]}}`,
+			want: "cannot archive and delete Entries of types page or settings",
+		},
+		{
+			name: "own assets except one",
+			policy: `{"effect": "allow", "actions": ["read", "update"], "constraint": {"and": [
+				{"equals": [{"doc": "sys.type"}, "Asset"]},
+				{"equals": [{"doc": "sys.createdBy.sys.id"}, "User.current()"]},
+				{"not": {"equals": [{"doc": "sys.id"}, "logo"]}}
+			]}}`,
+			want: "can read and update Assets created by themselves not with ID logo",
+		},
+		{
+			name:   "or and a field",
+			policy: `{"effect": "allow", "actions": ["update"], "constraint": {"or": [{"paths": [{"doc": "fields.title.de-DE"}]}, {"equals": [{"doc": "sys.type"}, "Asset"]}]}}`,
+			want:   "can update content (in field title in de-DE or that are Assets)",
+		},
+		{
+			name:   "unknown operator",
+			policy: `{"effect": "allow", "actions": ["read"], "constraint": {"matches": "x"}}`,
+			want:   `can read content matching {"matches":"x"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, policySummary(decodePolicy(t, tt.policy)))
+		})
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	translator := client.Role{
+		Name: "Translator",
+		Policies: []client.Policy{
+			decodePolicy(t, `{"effect": "allow", "actions": ["read"], "constraint": {"and": [{"equals": [{"doc": "sys.type"}, "Entry"]}]}}`),
+			decodePolicy(t, `{"effect": "allow", "actions": ["update"], "constraint": {"and": [
+				{"equals": [{"doc": "sys.type"}, "Entry"]},
+				{"paths": [{"doc": "fields.%.de-DE"}, {"doc": "fields.title.fr-FR"}]}
+			]}}`),
+			decodePolicy(t, `{"effect": "deny", "actions": "all", "constraint": {"and": [
+				{"equals": [{"doc": "sys.type"}, "Entry"]},
+				{"equals": [{"doc": "sys.contentType.sys.id"}, "legal"]}
+			]}}`),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		action string
+		doc    policyDoc
+		want   bool
+	}{
+		{"read entries", "read", policyDoc{"sys.type": "Entry"}, true},
+		{"read assets", "read", policyDoc{"sys.type": "Asset"}, false},
+		{"update a locale", "update", policyDoc{"sys.type": "Entry", policyLocale: "de-DE"}, true},
+		{"update some fields of a locale", "update", policyDoc{"sys.type": "Entry", policyLocale: "fr-FR"}, true},
+		{"update another locale", "update", policyDoc{"sys.type": "Entry", policyLocale: "en-US"}, false},
+		{"publish", "publish", policyDoc{"sys.type": "Entry"}, false},
+		{"denied content type", "read", policyDoc{"sys.type": "Entry", "sys.contentType.sys.id": "legal"}, false},
+		{"other content type", "read", policyDoc{"sys.type": "Entry", "sys.contentType.sys.id": "blogPost"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, roleAllows(translator, tt.action, tt.doc))
+		})
+	}
+
+	require.Equal(t, "can read Entries; can update Entries in de-DE or in field title in fr-FR; cannot do anything with Entries of type legal", roleSummary(translator))
+	require.Equal(t, "no access to content", roleSummary(client.Role{}))
+}
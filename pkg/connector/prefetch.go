@@ -0,0 +1,189 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultMaxConcurrency = 4
+
+// keyedCache caches a value per key, for ttl unless it is 0. Concurrent gets of a missing key share a single
+// fetch, and fetches of different keys don't wait on each other. Failed fetches aren't cached.
+type keyedCache[T any] struct {
+	fetch  func(ctx context.Context, key string) (T, error)
+	ttl    time.Duration
+	now    func() time.Time
+	group  singleflight.Group
+	mu     sync.RWMutex
+	values map[string]cachedValue[T]
+}
+
+type cachedValue[T any] struct {
+	value     T
+	fetchedAt time.Time
+}
+
+func newKeyedCache[T any](ttl time.Duration, fetch func(ctx context.Context, key string) (T, error)) *keyedCache[T] {
+	return &keyedCache[T]{
+		fetch:  fetch,
+		ttl:    ttl,
+		now:    time.Now,
+		values: make(map[string]cachedValue[T]),
+	}
+}
+
+func (c *keyedCache[T]) get(ctx context.Context, key string) (T, error) {
+	return c.getFresh(ctx, key, c.ttl)
+}
+
+// refresh fetches the key again unless its value is less than minAge old.
+func (c *keyedCache[T]) refresh(ctx context.Context, key string, minAge time.Duration) (T, error) {
+	return c.getFresh(ctx, key, minAge)
+}
+
+// reset forgets every cached value, the next get of each key fetches it again.
+func (c *keyedCache[T]) reset() {
+	c.mu.Lock()
+	c.values = make(map[string]cachedValue[T])
+	c.mu.Unlock()
+}
+
+func (c *keyedCache[T]) getFresh(ctx context.Context, key string, maxAge time.Duration) (T, error) {
+	c.mu.RLock()
+	cached, ok := c.values[key]
+	c.mu.RUnlock()
+	if ok && (maxAge == 0 || c.now().Sub(cached.fetchedAt) < maxAge) {
+		return cached.value, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		value, err := c.fetch(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.values[key] = cachedValue[T]{
+			value:     value,
+			fetchedAt: c.now(),
+		}
+		c.mu.Unlock()
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v.(T), nil
+}
+
+// prefetch is a fetch started ahead of the call that needs it.
+type prefetch[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// prefetcher runs fetches in the background, at most limit at a time, and hands each result
+// to the first take of its key.
+type prefetcher[T any] struct {
+	fetch   func(ctx context.Context, key string) (T, error)
+	sem     *semaphore.Weighted
+	mu      sync.Mutex
+	pending map[string]*prefetch[T]
+	// the context of the current listing's fetches, nil until one starts
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newPrefetcher[T any](limit int, fetch func(ctx context.Context, key string) (T, error)) *prefetcher[T] {
+	if limit < 1 {
+		limit = defaultMaxConcurrency
+	}
+
+	return &prefetcher[T]{
+		fetch:   fetch,
+		sem:     semaphore.NewWeighted(int64(limit)),
+		pending: make(map[string]*prefetch[T]),
+	}
+}
+
+// reset starts a new listing: the fetches of the previous one are cancelled and their results forgotten,
+// so a sync is never served what an earlier one prefetched.
+// The fetches of the listing outlive ctx, the request that starts it usually returns long before the results
+// are taken, they only end with the next reset.
+func (p *prefetcher[T]) reset(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.ctx, p.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	p.pending = make(map[string]*prefetch[T])
+}
+
+// start fetches the key in the background as part of the current listing, unless it is already pending.
+func (p *prefetcher[T]) start(ctx context.Context, key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx == nil {
+		p.ctx, p.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+
+	if _, ok := p.pending[key]; ok {
+		return
+	}
+
+	f := &prefetch[T]{
+		done: make(chan struct{}),
+	}
+	p.pending[key] = f
+
+	ctx = p.ctx
+	go func() {
+		defer close(f.done)
+
+		err := p.sem.Acquire(ctx, 1)
+		if err != nil {
+			f.err = err
+			return
+		}
+		defer p.sem.Release(1)
+
+		f.value, f.err = p.fetch(ctx, key)
+	}()
+}
+
+// take returns the prefetched value of the key and forgets it, so a later take fetches again.
+// Without a pending prefetch, or when it failed, the key is fetched in the calling goroutine.
+func (p *prefetcher[T]) take(ctx context.Context, key string) (T, error) {
+	p.mu.Lock()
+	f, ok := p.pending[key]
+	delete(p.pending, key)
+	p.mu.Unlock()
+
+	if ok {
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+
+		if f.err == nil {
+			return f.value, nil
+		}
+		ctxzap.Extract(ctx).Debug("baton-contentful: prefetch failed, fetching again", zap.String("key", key), zap.Error(f.err))
+	}
+
+	return p.fetch(ctx, key)
+}
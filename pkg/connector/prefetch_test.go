@@ -0,0 +1,234 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedCacheSharesFetch(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cache := newKeyedCache(0, func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "roles of " + key, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.get(context.Background(), "space")
+			assert.NoError(t, err)
+			assert.Equal(t, "roles of space", value)
+		}()
+	}
+
+	// give every goroutine the chance to join the pending fetch
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	value, err := cache.get(context.Background(), "space")
+	require.NoError(t, err)
+	require.Equal(t, "roles of space", value)
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestKeyedCacheFetchesKeysIndependently(t *testing.T) {
+	release := make(chan struct{})
+	cache := newKeyedCache(0, func(ctx context.Context, key string) (string, error) {
+		if key == "slow" {
+			<-release
+		}
+		return key, nil
+	})
+
+	slow := make(chan struct{})
+	go func() {
+		defer close(slow)
+		_, _ = cache.get(context.Background(), "slow")
+	}()
+
+	fast := make(chan struct{})
+	go func() {
+		defer close(fast)
+		_, _ = cache.get(context.Background(), "fast")
+	}()
+
+	select {
+	case <-fast:
+	case <-time.After(time.Second):
+		t.Fatal("fetch of one key waited on the fetch of another")
+	}
+
+	close(release)
+	<-slow
+}
+
+func TestKeyedCacheDoesNotCacheErrors(t *testing.T) {
+	var calls atomic.Int32
+	cache := newKeyedCache(0, func(ctx context.Context, key string) (string, error) {
+		if calls.Add(1) == 1 {
+			return "", errors.New("rate limited")
+		}
+		return key, nil
+	})
+
+	_, err := cache.get(context.Background(), "space")
+	require.Error(t, err)
+
+	value, err := cache.get(context.Background(), "space")
+	require.NoError(t, err)
+	require.Equal(t, "space", value)
+	require.EqualValues(t, 2, calls.Load())
+}
+
+func TestKeyedCacheExpiry(t *testing.T) {
+	var calls atomic.Int32
+	cache := newKeyedCache(10*time.Minute, func(ctx context.Context, key string) (int32, error) {
+		return calls.Add(1), nil
+	})
+	now := time.Now()
+	cache.now = func() time.Time {
+		return now
+	}
+
+	value, err := cache.get(context.Background(), "space")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, value)
+
+	// a refresh within minAge of the fetch keeps the cached value
+	now = now.Add(30 * time.Second)
+	value, err = cache.refresh(context.Background(), "space", time.Minute)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, value)
+
+	now = now.Add(time.Minute)
+	value, err = cache.refresh(context.Background(), "space", time.Minute)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, value)
+
+	value, err = cache.get(context.Background(), "space")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, value)
+
+	now = now.Add(10 * time.Minute)
+	value, err = cache.get(context.Background(), "space")
+	require.NoError(t, err)
+	require.EqualValues(t, 3, value)
+}
+
+func TestPrefetcherBoundsConcurrency(t *testing.T) {
+	const limit = 3
+
+	var inFlight, maxInFlight atomic.Int32
+	p := newPrefetcher(limit, func(ctx context.Context, key string) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return "members of " + key, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := range 20 {
+		p.start(ctx, fmt.Sprintf("space-%d", i))
+	}
+	// prefetches outlive the request that started them
+	cancel()
+
+	for i := range 20 {
+		key := fmt.Sprintf("space-%d", i)
+		value, err := p.take(context.Background(), key)
+		require.NoError(t, err)
+		require.Equal(t, "members of "+key, value)
+	}
+
+	require.LessOrEqual(t, maxInFlight.Load(), int32(limit))
+	require.Positive(t, maxInFlight.Load())
+}
+
+func TestPrefetcherTake(t *testing.T) {
+	var calls atomic.Int32
+	p := newPrefetcher(2, func(ctx context.Context, key string) (string, error) {
+		if calls.Add(1) == 1 {
+			return "", errors.New("rate limited")
+		}
+		return key, nil
+	})
+
+	// a failed prefetch is fetched again
+	p.start(context.Background(), "space")
+	value, err := p.take(context.Background(), "space")
+	require.NoError(t, err)
+	require.Equal(t, "space", value)
+	require.EqualValues(t, 2, calls.Load())
+
+	// the prefetched value was handed out, without a new prefetch it is fetched in place
+	value, err = p.take(context.Background(), "space")
+	require.NoError(t, err)
+	require.Equal(t, "space", value)
+	require.EqualValues(t, 3, calls.Load())
+}
+
+func TestPrefetcherTakeHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	p := newPrefetcher(1, func(ctx context.Context, key string) (string, error) {
+		<-release
+		return key, nil
+	})
+
+	p.start(context.Background(), "space")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.take(ctx, "space")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPrefetcherResetForgetsThePreviousListing(t *testing.T) {
+	var calls atomic.Int32
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	p := newPrefetcher(2, func(ctx context.Context, key string) (int32, error) {
+		n := calls.Add(1)
+		if n == 1 {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return 0, ctx.Err()
+		}
+		return n, nil
+	})
+
+	p.reset(context.Background())
+	p.start(context.Background(), "space")
+	<-started
+
+	p.reset(context.Background())
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the prefetch of the previous listing wasn't cancelled")
+	}
+
+	// the previous listing's prefetch is gone, the key is fetched in place
+	value, err := p.take(context.Background(), "space")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, value)
+}
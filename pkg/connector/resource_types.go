@@ -28,3 +28,31 @@ var teamResourceType = &v2.ResourceType{
 	DisplayName: "Team",
 	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
 }
+
+// Invitees show up as invitations until they accept, memberships can point at them before that.
+var invitationResourceType = &v2.ResourceType{
+	Id:          "invitation",
+	DisplayName: "Invitation",
+}
+
+// Environments are listed under their space, their IDs are only unique within it.
+var environmentResourceType = &v2.ResourceType{
+	Id:          "environment",
+	DisplayName: "Environment",
+}
+
+var localeResourceType = &v2.ResourceType{
+	Id:          "locale",
+	DisplayName: "Locale",
+}
+
+var contentTypeResourceType = &v2.ResourceType{
+	Id:          "content_type",
+	DisplayName: "Content Type",
+}
+
+// Environment aliases are listed under their space, their IDs are only unique within it.
+var environmentAliasResourceType = &v2.ResourceType{
+	Id:          "environment_alias",
+	DisplayName: "Environment Alias",
+}
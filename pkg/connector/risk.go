@@ -0,0 +1,133 @@
+package connector
+
+import (
+	"fmt"
+	"slices"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+)
+
+const riskHigh = "high"
+
+// The permissions of a space role, the parts of a space other than content.
+const (
+	permissionContentModel    = "ContentModel"
+	permissionSettings        = "Settings"
+	permissionContentDelivery = "ContentDelivery"
+)
+
+// riskRule classifies the entitlements it matches, it matches an entitlement when any of its conditions does.
+type riskRule struct {
+	Name       string `json:"name"`
+	Risk       string `json:"risk"`
+	Privileged bool   `json:"privileged"`
+	// organization roles: owner, admin, developer or member
+	OrgRoles   []string `json:"orgRoles"`
+	SpaceAdmin bool     `json:"spaceAdmin"`
+	// space role names
+	SpaceRoles []string `json:"spaceRoles"`
+	// ContentModel, Settings or ContentDelivery, a space role matches when it has "all" of one of them
+	AllPermissions []string `json:"allPermissions"`
+}
+
+// riskRules are checked in order, an entitlement is classified by the first rule it matches.
+type riskRules []riskRule
+
+// defaultRiskRules classify org owners and admins, space admins, and space roles that can change
+// the content model or the space settings as privileged.
+var defaultRiskRules = riskRules{
+	{
+		Name:       "organization owner or admin",
+		Risk:       riskHigh,
+		Privileged: true,
+		OrgRoles:   []string{orgOwner, orgAdmin},
+	},
+	{
+		Name:       "space admin",
+		Risk:       riskHigh,
+		Privileged: true,
+		SpaceAdmin: true,
+	},
+	{
+		Name:           "content model or settings",
+		Risk:           riskHigh,
+		Privileged:     true,
+		AllPermissions: []string{permissionContentModel, permissionSettings},
+	},
+}
+
+// loadRiskRules reads a YAML or JSON file with the rules under "rules", in place of the default rules.
+func loadRiskRules(path string) (riskRules, error) {
+	var file struct {
+		Rules riskRules `json:"rules"`
+	}
+	err := decodeConfigFile(path, &file)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to read risk rules %s: %w", path, err)
+	}
+
+	for i, rule := range file.Rules {
+		if rule.Name == "" || rule.Risk == "" {
+			return nil, fmt.Errorf("baton-contentful: risk rule %d in %s needs a name and a risk", i+1, path)
+		}
+		for _, permission := range rule.AllPermissions {
+			switch permission {
+			case permissionContentModel, permissionSettings, permissionContentDelivery:
+			default:
+				return nil, fmt.Errorf("baton-contentful: risk rule %s in %s has unknown permission %s", rule.Name, path, permission)
+			}
+		}
+	}
+	return file.Rules, nil
+}
+
+func (r riskRules) first(match func(rule riskRule) bool) *riskRule {
+	for i := range r {
+		if match(r[i]) {
+			return &r[i]
+		}
+	}
+	return nil
+}
+
+func (r riskRules) classifyOrgRole(role string) *riskRule {
+	return r.first(func(rule riskRule) bool {
+		return slices.Contains(rule.OrgRoles, role)
+	})
+}
+
+func (r riskRules) classifySpaceAdmin() *riskRule {
+	return r.first(func(rule riskRule) bool {
+		return rule.SpaceAdmin
+	})
+}
+
+func (r riskRules) classifySpaceRole(role client.Role) *riskRule {
+	permissions := map[string]any{
+		permissionContentModel:    role.Permissions.ContentModel,
+		permissionSettings:        role.Permissions.Settings,
+		permissionContentDelivery: role.Permissions.ContentDelivery,
+	}
+
+	return r.first(func(rule riskRule) bool {
+		if slices.Contains(rule.SpaceRoles, role.Name) {
+			return true
+		}
+		for _, permission := range rule.AllPermissions {
+			if slices.Equal(normalizeActions(permissions[permission]), []string{policyAllActions}) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// riskAnnotation is attached to the entitlements a risk rule classifies.
+func riskAnnotation(rule *riskRule) *contentfulv1.RiskClassification {
+	return &contentfulv1.RiskClassification{
+		Risk:       rule.Risk,
+		Privileged: rule.Privileged,
+		Rule:       rule.Name,
+	}
+}
@@ -0,0 +1,118 @@
+package connector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRiskRules(t *testing.T) {
+	require.NotNil(t, defaultRiskRules.classifyOrgRole(orgOwner))
+	require.NotNil(t, defaultRiskRules.classifyOrgRole(orgAdmin))
+	require.Nil(t, defaultRiskRules.classifyOrgRole(orgDeveloper))
+	require.Nil(t, defaultRiskRules.classifyOrgRole(orgMember))
+
+	rule := defaultRiskRules.classifySpaceAdmin()
+	require.NotNil(t, rule)
+	require.Equal(t, riskHigh, rule.Risk)
+	require.True(t, rule.Privileged)
+
+	tests := []struct {
+		name        string
+		permissions client.Permissions
+		privileged  bool
+	}{
+		{"content model", client.Permissions{ContentModel: "all"}, true},
+		{"settings", client.Permissions{Settings: []any{"all"}}, true},
+		{"some content model actions", client.Permissions{ContentModel: []any{"read"}, Settings: []any{}}, false},
+		{"content delivery", client.Permissions{ContentDelivery: "all"}, false},
+		{"none", client.Permissions{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := defaultRiskRules.classifySpaceRole(client.Role{Name: "Developer", Permissions: tt.permissions})
+			require.Equal(t, tt.privileged, rule != nil)
+		})
+	}
+
+	var none riskRules
+	require.Nil(t, none.classifySpaceAdmin())
+}
+
+func TestLoadRiskRules(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - name: publishers
+    risk: medium
+    spaceRoles: [Publisher]
+  - name: developers
+    risk: high
+    privileged: true
+    orgRoles: [owner, admin, developer]
+    allPermissions: [ContentDelivery]
+`), 0o600))
+
+	rules, err := loadRiskRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	rule := rules.classifySpaceRole(client.Role{Name: "Publisher", Permissions: client.Permissions{ContentDelivery: "all"}})
+	require.Equal(t, "publishers", rule.Name)
+	require.False(t, rule.Privileged)
+
+	rule = rules.classifyOrgRole(orgDeveloper)
+	require.Equal(t, "developers", rule.Name)
+	require.Nil(t, rules.classifySpaceAdmin())
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("rules: [{name: x, risk: high, allPermissions: [Content]}]"), 0o600))
+
+	_, err = loadRiskRules(invalid)
+	require.Error(t, err)
+}
+
+func TestEntitlementsAnnotateRisk(t *testing.T) {
+	s := newFakeContentful(t)
+	syncers := fakeSyncers(t, s)
+
+	risks := make(map[string]string)
+	for _, resource := range []struct {
+		resourceType *v2.ResourceType
+		id           string
+	}{
+		{orgResourceType, fakeOrgID},
+		{spaceResourceType, "space-blog"},
+	} {
+		syncer := syncers[resource.resourceType.Id]
+		r := findResource(t, syncer, nil, resource.id)
+		for _, e := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+			return syncer.Entitlements(context.Background(), r, pToken)
+		}) {
+			risk := &contentfulv1.RiskClassification{}
+			annos := annotations.Annotations(e.Annotations)
+			ok, err := annos.Pick(risk)
+			require.NoError(t, err)
+			if ok {
+				require.True(t, risk.Privileged)
+				risks[e.Id] = risk.Risk + " " + risk.Rule
+			}
+		}
+	}
+	require.Equal(t, map[string]string{
+		"organization:org-acme:owner": "high organization owner or admin",
+		"organization:org-acme:admin": "high organization owner or admin",
+		"space:space-blog:admin":      "high space admin",
+	}, risks)
+}
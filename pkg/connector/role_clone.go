@@ -0,0 +1,177 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	roleCreated   = "create"
+	roleUpdated   = "update"
+	roleUnchanged = "unchanged"
+)
+
+// roleChange is what cloning a template role does to the target space's role of the same name.
+type roleChange struct {
+	template client.Role
+	// nil when the target space has no role of that name
+	target      *client.Role
+	change      string
+	differences []string
+}
+
+func (c roleChange) String() string {
+	switch c.change {
+	case roleCreated:
+		return fmt.Sprintf("%s: missing from the target space", c.template.Name)
+	case roleUpdated:
+		return fmt.Sprintf("%s: %s differ from the template", c.template.Name, strings.Join(c.differences, ", "))
+	default:
+		return fmt.Sprintf("%s: unchanged", c.template.Name)
+	}
+}
+
+// roleDifferences returns which of the description, policies and permissions of the roles differ.
+func roleDifferences(want, got client.Role) ([]string, error) {
+	var rv []string
+	if want.Description != got.Description {
+		rv = append(rv, "description")
+	}
+
+	for _, part := range []struct {
+		name      string
+		want, got any
+	}{
+		{"policies", want.Policies, got.Policies},
+		{"permissions", want.Permissions, got.Permissions},
+	} {
+		// compared as JSON, the actions and constraints are free form
+		wantJSON, err := json.Marshal(part.want)
+		if err != nil {
+			return nil, err
+		}
+		gotJSON, err := json.Marshal(part.got)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(wantJSON, gotJSON) {
+			rv = append(rv, part.name)
+		}
+	}
+
+	return rv, nil
+}
+
+// diffRoles matches the template roles to the target roles by name, roles only in the target are left alone.
+func diffRoles(template, target []client.Role) ([]roleChange, error) {
+	byName := make(map[string]*client.Role, len(target))
+	for i := range target {
+		byName[target[i].Name] = &target[i]
+	}
+
+	rv := make([]roleChange, 0, len(template))
+	for _, role := range template {
+		existing, ok := byName[role.Name]
+		if !ok {
+			rv = append(rv, roleChange{
+				template: role,
+				change:   roleCreated,
+			})
+			continue
+		}
+
+		differences, err := roleDifferences(role, *existing)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to compare role %s: %w", role.Name, err)
+		}
+
+		change := roleUnchanged
+		if len(differences) > 0 {
+			change = roleUpdated
+		}
+		rv = append(rv, roleChange{
+			template:    role,
+			target:      existing,
+			change:      change,
+			differences: differences,
+		})
+	}
+
+	return rv, nil
+}
+
+func (d *Connector) cloneSpaceRoles(ctx context.Context, args map[string]*structpb.Value) (*structpb.Struct, error) {
+	templateSpaceID := args["template_space_id"].GetStringValue()
+	targetSpaceID := args["target_space_id"].GetStringValue()
+	dryRun := args["dry_run"].GetBoolValue()
+
+	if templateSpaceID == targetSpaceID {
+		return nil, status.Errorf(codes.InvalidArgument, "baton-contentful: the template and target space are the same")
+	}
+
+	spaces := newSpaceBuilder(d.client, nil, d.maxConcurrency, nil, nil, nil)
+	templateRoles, err := spaces.listRoles(ctx, templateSpaceID)
+	if err != nil {
+		return nil, err
+	}
+	targetRoles, err := spaces.listRoles(ctx, targetSpaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffRoles(templateRoles, targetRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string)
+	diff := make([]string, 0, len(changes))
+	for _, change := range changes {
+		result[change.change] = append(result[change.change], change.template.Name)
+		diff = append(diff, change.String())
+
+		if dryRun {
+			continue
+		}
+
+		switch change.change {
+		case roleCreated:
+			_, err = d.client.CreateSpaceRole(ctx, targetSpaceID, &change.template)
+		case roleUpdated:
+			role := *change.target
+			role.Description = change.template.Description
+			role.Policies = change.template.Policies
+			role.Permissions = change.template.Permissions
+			_, err = d.client.UpdateSpaceRole(ctx, targetSpaceID, &role)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to %s role %s in space %s: %w", change.change, change.template.Name, targetSpaceID, err)
+		}
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"dry_run":   structpb.NewBoolValue(dryRun),
+			"created":   structpb.NewListValue(&structpb.ListValue{Values: stringValues(result[roleCreated])}),
+			"updated":   structpb.NewListValue(&structpb.ListValue{Values: stringValues(result[roleUpdated])}),
+			"unchanged": structpb.NewListValue(&structpb.ListValue{Values: stringValues(result[roleUnchanged])}),
+			"diff":      structpb.NewListValue(&structpb.ListValue{Values: stringValues(diff)}),
+		},
+	}, nil
+}
+
+func stringValues(values []string) []*structpb.Value {
+	rv := make([]*structpb.Value, 0, len(values))
+	for _, v := range values {
+		rv = append(rv, structpb.NewStringValue(v))
+	}
+	return rv
+}
@@ -0,0 +1,50 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRoles(t *testing.T) {
+	editor := client.Role{
+		Name:        "Editor",
+		Description: "Edits entries",
+		Policies: []client.Policy{
+			{Effect: "allow", Actions: "all", Constraint: map[string]any{"sys.type": "Entry"}},
+		},
+		Permissions: client.Permissions{ContentModel: []string{"read"}},
+	}
+	author := client.Role{
+		Name:        "Author",
+		Description: "Writes drafts",
+		Policies: []client.Policy{
+			{Effect: "allow", Actions: []string{"read", "create"}},
+		},
+	}
+
+	driftedEditor := editor
+	driftedEditor.Policies = []client.Policy{
+		{Effect: "allow", Actions: []string{"read"}, Constraint: map[string]any{"sys.type": "Entry"}},
+	}
+	translator := client.Role{Name: "Translator"}
+
+	changes, err := diffRoles(
+		[]client.Role{editor, author},
+		[]client.Role{driftedEditor, translator},
+	)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	require.Equal(t, roleUpdated, changes[0].change)
+	require.Equal(t, []string{"policies"}, changes[0].differences)
+	require.Equal(t, "Editor: policies differ from the template", changes[0].String())
+
+	require.Equal(t, roleCreated, changes[1].change)
+	require.Nil(t, changes[1].target)
+
+	changes, err = diffRoles([]client.Role{editor}, []client.Role{editor})
+	require.NoError(t, err)
+	require.Equal(t, roleUnchanged, changes[0].change)
+}
@@ -0,0 +1,230 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+// roleBaseline is the canonical definition of space roles, by name. Roles it doesn't name aren't checked.
+type roleBaseline map[string]client.Role
+
+// decodeConfigFile decodes a YAML or JSON file into v. YAML is a superset of JSON, going through JSON
+// decodes it with the json tags of v, the same field names as the API.
+func decodeConfigFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// loadRoleBaseline reads a YAML or JSON file with the roles under "roles", each written the way the
+// Content Management API returns roles.
+func loadRoleBaseline(path string) (roleBaseline, error) {
+	var file struct {
+		Roles []client.Role `json:"roles"`
+	}
+	err := decodeConfigFile(path, &file)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to read role baseline %s: %w", path, err)
+	}
+
+	if len(file.Roles) == 0 {
+		return nil, fmt.Errorf("baton-contentful: role baseline %s has no roles", path)
+	}
+	rv := make(roleBaseline, len(file.Roles))
+	for _, role := range file.Roles {
+		if role.Name == "" {
+			return nil, fmt.Errorf("baton-contentful: role baseline %s has a role without a name", path)
+		}
+		if _, ok := rv[role.Name]; ok {
+			return nil, fmt.Errorf("baton-contentful: role baseline %s has role %s more than once", path, role.Name)
+		}
+		rv[role.Name] = role
+	}
+	return rv, nil
+}
+
+// policyKey identifies a policy by its effect and constraint, policies with the same key but different
+// actions diverge rather than one being missing and the other extra.
+func policyKey(policy client.Policy) (string, error) {
+	constraint, err := json.Marshal(policy.Constraint)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s where %s", policy.Effect, constraint), nil
+}
+
+// keyedPolicy is a policy's key and its normalized actions, as JSON, so the order of the actions doesn't matter.
+type keyedPolicy struct {
+	key     string
+	actions string
+}
+
+func keyPolicies(policies []client.Policy) ([]keyedPolicy, map[string]string, error) {
+	rv := make([]keyedPolicy, 0, len(policies))
+	byKey := make(map[string]string, len(policies))
+	for _, policy := range policies {
+		key, err := policyKey(policy)
+		if err != nil {
+			return nil, nil, err
+		}
+		actions, err := json.Marshal(normalizeActions(policy.Actions))
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, keyedPolicy{key: key, actions: string(actions)})
+		byKey[key] = string(actions)
+	}
+	return rv, byKey, nil
+}
+
+// roleDrift returns a warning for every policy of the baseline role the role is missing, every policy it has on top
+// of them, every policy whose actions diverge, and for permissions that differ.
+func roleDrift(baseline, role client.Role) ([]string, error) {
+	want, wantByKey, err := keyPolicies(baseline.Policies)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to compare role %s: %w", role.Name, err)
+	}
+	got, gotByKey, err := keyPolicies(role.Policies)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to compare role %s: %w", role.Name, err)
+	}
+
+	var rv []string
+	for _, policy := range want {
+		actions, ok := gotByKey[policy.key]
+		switch {
+		case !ok:
+			rv = append(rv, fmt.Sprintf("missing policy: %s %s", policy.actions, policy.key))
+		case actions != policy.actions:
+			rv = append(rv, fmt.Sprintf("diverging policy: %s: actions %s instead of %s", policy.key, actions, policy.actions))
+		}
+	}
+	for _, policy := range got {
+		if _, ok := wantByKey[policy.key]; !ok {
+			rv = append(rv, fmt.Sprintf("extra policy: %s %s", policy.actions, policy.key))
+		}
+	}
+
+	if !permissionsEqual(baseline.Permissions, role.Permissions) {
+		rv = append(rv, "permissions differ from the baseline")
+	}
+
+	return rv, nil
+}
+
+// permissionsEqual compares the normalized actions of the permissions, so their order doesn't matter.
+func permissionsEqual(a, b client.Permissions) bool {
+	for _, actions := range [][2]any{
+		{a.ContentModel, b.ContentModel},
+		{a.Settings, b.Settings},
+		{a.ContentDelivery, b.ContentDelivery},
+		{a.Environments, b.Environments},
+		{a.EnvironmentAliases, b.EnvironmentAliases},
+	} {
+		if !slices.Equal(normalizeActions(actions[0]), normalizeActions(actions[1])) {
+			return false
+		}
+	}
+	return true
+}
+
+// roleDriftAnnotation is attached to the entitlement of a space role that drifted from the baseline.
+func roleDriftAnnotation(warnings []string) *contentfulv1.BaselineDrift {
+	return &contentfulv1.BaselineDrift{
+		Differences: warnings,
+	}
+}
+
+// RoleDrift is how a space role differs from the baseline role of the same name.
+type RoleDrift struct {
+	SpaceID   string   `json:"spaceId"`
+	SpaceName string   `json:"spaceName"`
+	Role      string   `json:"role"`
+	Warnings  []string `json:"warnings"`
+}
+
+// RoleDrift compares the roles of every space to the role baseline the connector was created with.
+// Baseline roles a space doesn't have are reported as missing.
+func (d *Connector) RoleDrift(ctx context.Context) ([]RoleDrift, error) {
+	if d.roleBaseline == nil {
+		return nil, fmt.Errorf("baton-contentful: no role baseline")
+	}
+
+	names := make([]string, 0, len(d.roleBaseline))
+	for name := range d.roleBaseline {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	spaces := newSpaceBuilder(d.client, nil, d.maxConcurrency, nil, nil, nil)
+	var rv []RoleDrift
+	var offset int
+	for {
+		res, err := d.client.ListSpaces(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list spaces: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, space := range res.Items {
+			roles, err := spaces.listRoles(ctx, space.Sys.ID)
+			if err != nil {
+				return nil, err
+			}
+			byName := make(map[string]client.Role, len(roles))
+			for _, role := range roles {
+				byName[role.Name] = role
+			}
+
+			for _, name := range names {
+				drift := RoleDrift{
+					SpaceID:   space.Sys.ID,
+					SpaceName: space.Name,
+					Role:      name,
+				}
+
+				role, ok := byName[name]
+				if !ok {
+					drift.Warnings = []string{"missing from the space"}
+					rv = append(rv, drift)
+					continue
+				}
+
+				drift.Warnings, err = roleDrift(d.roleBaseline[name], role)
+				if err != nil {
+					return nil, err
+				}
+				if len(drift.Warnings) > 0 {
+					rv = append(rv, drift)
+				}
+			}
+		}
+
+		offset += len(res.Items)
+	}
+
+	return rv, nil
+}
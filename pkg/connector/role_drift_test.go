@@ -0,0 +1,133 @@
+package connector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRoleBaseline(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "roles.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`
+roles:
+  - name: Editor
+    description: Edits entries
+    policies:
+      - effect: allow
+        actions: all
+        constraint:
+          and:
+            - equals: [{doc: sys.type}, Entry]
+    permissions:
+      ContentModel: [read]
+      Settings: []
+      ContentDelivery: all
+  - name: Translator
+    policies:
+      - effect: allow
+        actions: [read, update]
+`), 0o600))
+
+	baseline, err := loadRoleBaseline(yamlPath)
+	require.NoError(t, err)
+	require.Len(t, baseline, 2)
+	require.Equal(t, "all", baseline["Editor"].Policies[0].Actions)
+	require.Equal(t, []any{"read"}, baseline["Editor"].Permissions.ContentModel)
+	require.Equal(t, "all", baseline["Editor"].Permissions.ContentDelivery)
+	require.Equal(t, []any{"read", "update"}, baseline["Translator"].Policies[0].Actions)
+
+	jsonPath := filepath.Join(dir, "roles.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"roles": [{"name": "Author", "policies": [{"effect": "allow", "actions": ["read"]}]}]}`), 0o600))
+
+	baseline, err = loadRoleBaseline(jsonPath)
+	require.NoError(t, err)
+	require.Contains(t, baseline, "Author")
+
+	duplicatePath := filepath.Join(dir, "duplicate.yaml")
+	require.NoError(t, os.WriteFile(duplicatePath, []byte("roles: [{name: Editor}, {name: Editor}]"), 0o600))
+
+	_, err = loadRoleBaseline(duplicatePath)
+	require.Error(t, err)
+}
+
+func TestRoleDrift(t *testing.T) {
+	entries := map[string]any{"sys.type": "Entry"}
+	assets := map[string]any{"sys.type": "Asset"}
+	baseline := client.Role{
+		Name: "Editor",
+		Policies: []client.Policy{
+			{Effect: "allow", Actions: "all", Constraint: entries},
+			{Effect: "allow", Actions: []any{"read", "publish"}, Constraint: assets},
+		},
+		Permissions: client.Permissions{ContentModel: []string{"read", "create"}},
+	}
+
+	warnings, err := roleDrift(baseline, baseline)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	// the same actions, in another order
+	reordered := client.Role{
+		Name: "Editor",
+		Policies: []client.Policy{
+			{Effect: "allow", Actions: []any{"all"}, Constraint: entries},
+			{Effect: "allow", Actions: []any{"publish", "read"}, Constraint: assets},
+		},
+		Permissions: client.Permissions{ContentModel: []any{"create", "read"}},
+	}
+
+	warnings, err = roleDrift(baseline, reordered)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	drifted := client.Role{
+		Name: "Editor",
+		Policies: []client.Policy{
+			{Effect: "allow", Actions: []any{"read", "update"}, Constraint: entries},
+			{Effect: "deny", Actions: "all", Constraint: assets},
+		},
+		Permissions: client.Permissions{ContentModel: []string{"read"}},
+	}
+
+	warnings, err = roleDrift(baseline, drifted)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`diverging policy: allow where {"sys.type":"Entry"}: actions ["read","update"] instead of ["all"]`,
+		`missing policy: ["publish","read"] allow where {"sys.type":"Asset"}`,
+		`extra policy: ["all"] deny where {"sys.type":"Asset"}`,
+		"permissions differ from the baseline",
+	}, warnings)
+}
+
+func TestSpaceEntitlementsAnnotateBaselineDrift(t *testing.T) {
+	s := newFakeContentful(t)
+	baseline := roleBaseline{"Editor": {Name: "Editor", Permissions: client.Permissions{ContentModel: []any{"read"}, Settings: []any{}, ContentDelivery: []any{}}}}
+	o := newSpaceBuilder(s.Client(t), nil, 2, nil, baseline, nil)
+	resource := findResource(t, o, nil, "space-blog")
+
+	drifts := make(map[string][]string)
+	for _, e := range collectPages(t, func(pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+		return o.Entitlements(context.Background(), resource, pToken)
+	}) {
+		drift := &contentfulv1.BaselineDrift{}
+		annos := annotations.Annotations(e.Annotations)
+		ok, err := annos.Pick(drift)
+		require.NoError(t, err)
+		if ok {
+			drifts[e.Slug] = drift.Differences
+		}
+	}
+	require.Equal(t, map[string][]string{
+		"Editor": {`extra policy: ["all"] allow where {"and":[{"equals":[{"doc":"sys.type"},"Entry"]}]}`},
+	}, drifts)
+}
@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
 	"github.com/conductorone/baton-contentful/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -14,99 +15,241 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-const spaceAdmin = "admin"
+const (
+	spaceAdmin         = "admin"
+	defaultSpaceLocale = "en-US"
+)
+
+const (
+	// roleCacheTTL is how long the roles of a space are cached before they are listed again.
+	roleCacheTTL = 10 * time.Minute
+	// roleRefillInterval is how often looking up an unknown role can list the roles of its space again.
+	roleRefillInterval = time.Minute
+	// usageCacheTTL is how long the API usage of the spaces is cached before it is fetched again.
+	usageCacheTTL = 10 * time.Minute
+)
 
 type spaceBuilder struct {
 	client *client.Client
-	// spaceId: role
-	spaceRoleCache map[string][]role
-	mu             *sync.Mutex
-}
-
-type role struct {
-	Id   string
-	Name string
+	// nil unless grants are synced incrementally
+	state *stateStore
+	// spaceId: roles
+	roles *keyedCache[[]client.Role]
+	// orgId: space memberships by space ID, listed once per sync
+	memberships *keyedCache[map[string][]client.SpaceMembership]
+	members     *prefetcher[[]client.SpaceMembership]
+	// nil when memberships are all granted to users
+	directory func(ctx context.Context) (*userDirectory, error)
+	// nil unless roles are checked for drift
+	baseline roleBaseline
+	// nil when entitlements aren't classified by risk
+	risk riskRules
+	// orgId: API usage by space ID
+	usage *keyedCache[map[string][]client.PeriodicUsage]
 }
 
-func (o *spaceBuilder) fillCache(ctx context.Context, spaceID string) error {
+func (o *spaceBuilder) listRoles(ctx context.Context, spaceID string) ([]client.Role, error) {
+	var rv []client.Role
 	var offset int
 	for {
 		res, err := o.client.ListSpaceRoles(ctx, spaceID, offset)
 		if err != nil {
-			return fmt.Errorf("baton-contentful: failed to list space roles: %w", err)
+			return nil, fmt.Errorf("baton-contentful: failed to list space roles: %w", err)
 		}
 
 		if len(res.Items) == 0 {
 			break
 		}
 
-		for _, role := range res.Items {
-			o.cacheSetRole(spaceID, role.Sys.ID, role.Name)
-		}
-
+		rv = append(rv, res.Items...)
 		offset += len(res.Items)
 	}
-	return nil
+	return rv, nil
 }
 
-func (o *spaceBuilder) cacheGetRoleName(ctx context.Context, spaceID, roleID string) (string, error) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
+// findRole returns the first role of the space that matches, or nil. When no role matches the roles are
+// listed again, at most once per roleRefillInterval, so roles created since they were cached are found.
+func (o *spaceBuilder) findRole(ctx context.Context, spaceID string, match func(role client.Role) bool) (*client.Role, error) {
+	roles, err := o.roles.get(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fill cache: %w", err)
+	}
 
-	// if no roles are cached for the space, we need to fill the cache
-	if len(o.spaceRoleCache[spaceID]) == 0 {
-		if err := o.fillCache(ctx, spaceID); err != nil {
-			return "", fmt.Errorf("failed to fill cache: %w", err)
+	for _, role := range roles {
+		if match(role) {
+			return &role, nil
 		}
 	}
 
-	for _, role := range o.spaceRoleCache[spaceID] {
-		if role.Id == roleID {
-			return role.Name, nil
+	roles, err = o.roles.refresh(ctx, spaceID, roleRefillInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refill cache: %w", err)
+	}
+
+	for _, role := range roles {
+		if match(role) {
+			return &role, nil
 		}
 	}
 
-	return "", fmt.Errorf("roleID %s not found in cache, spaceID: %s", roleID, spaceID)
+	return nil, nil
+}
+
+func (o *spaceBuilder) cacheGetRoleName(ctx context.Context, spaceID, roleID string) (string, error) {
+	role, err := o.findRole(ctx, spaceID, func(role client.Role) bool {
+		return role.Sys.ID == roleID
+	})
+	if err != nil {
+		return "", err
+	}
+	if role == nil {
+		return "", fmt.Errorf("roleID %s not found in cache, spaceID: %s", roleID, spaceID)
+	}
+
+	return role.Name, nil
 }
 
 func (o *spaceBuilder) cacheGetRoleID(ctx context.Context, spaceID, roleName string) (string, error) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
+	role, err := o.findRole(ctx, spaceID, func(role client.Role) bool {
+		return role.Name == roleName
+	})
+	if err != nil {
+		return "", err
+	}
+	if role == nil {
+		return "", fmt.Errorf("role %s not found in cache, spaceID: %s", roleName, spaceID)
+	}
 
-	// if no roles are cached for the space, we need to fill the cache
-	if len(o.spaceRoleCache[spaceID]) == 0 {
-		if err := o.fillCache(ctx, spaceID); err != nil {
-			return "", fmt.Errorf("failed to fill cache: %w", err)
-		}
+	return role.Sys.ID, nil
+}
+
+// listOrgSpaceMemberships returns every space membership of the organization by space ID, in one paginated
+// listing rather than one per space. Only the memberships updated since the previous sync are fetched when
+// grants are synced incrementally.
+func (o *spaceBuilder) listOrgSpaceMemberships(ctx context.Context, _ string) (map[string][]client.SpaceMembership, error) {
+	source := membershipSource[client.SpaceMembership]{
+		list: func(ctx context.Context, since time.Time, offset int) ([]client.SpaceMembership, error) {
+			var res *client.GetSpaceMembershipsResponse
+			var err error
+			if since.IsZero() {
+				res, err = o.client.ListOrganizationSpaceMemberships(ctx, offset)
+			} else {
+				res, err = o.client.ListOrganizationSpaceMembershipsUpdatedSince(ctx, since, offset)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("baton-contentful: failed to list space memberships: %w", err)
+			}
+			return res.Items, nil
+		},
+		count: func(ctx context.Context) (int, error) {
+			count, err := o.client.CountOrganizationSpaceMemberships(ctx)
+			if err != nil {
+				return 0, fmt.Errorf("baton-contentful: failed to count space memberships: %w", err)
+			}
+			return count, nil
+		},
+		sys: func(spaceMembership client.SpaceMembership) (string, time.Time) {
+			return spaceMembership.Sys.ID, spaceMembership.Sys.UpdatedAt
+		},
 	}
 
-	for _, role := range o.spaceRoleCache[spaceID] {
-		if role.Name == roleName {
-			return role.Id, nil
-		}
+	var memberships []client.SpaceMembership
+	var err error
+	if o.state == nil {
+		memberships, err = source.listAll(ctx, time.Time{})
+	} else {
+		memberships, err = syncMemberships(ctx, o.state, "space-memberships", source)
 	}
-	return "", fmt.Errorf("role %s not found in cache, spaceID: %s", roleName, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make(map[string][]client.SpaceMembership)
+	for _, spaceMembership := range memberships {
+		spaceID := spaceMembership.Sys.Space.Sys.ID
+		rv[spaceID] = append(rv[spaceID], spaceMembership)
+	}
+	return rv, nil
 }
 
-func (o *spaceBuilder) cacheSetRole(spaceId, roleID, roleName string) {
-	o.spaceRoleCache[spaceId] = append(o.spaceRoleCache[spaceId], role{
-		Id:   roleID,
-		Name: roleName,
-	})
+// fetchMembers is what a prefetch of a space runs, the grants need the role names too.
+func (o *spaceBuilder) fetchMembers(ctx context.Context, spaceID string) ([]client.SpaceMembership, error) {
+	_, err := o.roles.get(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := o.memberships.get(ctx, o.client.OrgID())
+	if err != nil {
+		return nil, err
+	}
+
+	return index[spaceID], nil
+}
+
+// spaceRoleEntitlementID is the ID of the entitlement of a space role, or of spaceAdmin.
+func spaceRoleEntitlementID(spaceID, roleName string) (string, error) {
+	spaceResourceID, err := resourceSdk.NewResourceID(spaceResourceType, spaceID)
+	if err != nil {
+		return "", err
+	}
+	return entitlement.NewEntitlementID(&v2.Resource{Id: spaceResourceID}, roleName), nil
+}
+
+// spaceRolesGrantableTo are the principals of the grants spaceRolesGrant makes: the space, and the users and invitees
+// holding the space roles once the grant is expanded.
+var spaceRolesGrantableTo = []*v2.ResourceType{spaceResourceType, userResourceType, invitationResourceType}
+
+// spaceRolesGrant grants the entitlement to the space, expanded to the members of the space roles.
+func spaceRolesGrant(resource *v2.Resource, entitlementName, spaceID string, roleEntitlementIDs []string) (*v2.Grant, error) {
+	spaceResourceID, err := resourceSdk.NewResourceID(spaceResourceType, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return grant.NewGrant(
+		resource,
+		entitlementName,
+		spaceResourceID,
+		grant.WithAnnotation(&v2.GrantExpandable{
+			EntitlementIds: roleEntitlementIDs,
+			Shallow:        true,
+		}),
+	), nil
+}
+
+func (o *spaceBuilder) listUsage(ctx context.Context, _ string) (map[string][]client.PeriodicUsage, error) {
+	return listSpaceUsage(ctx, o.client)
 }
 
 func (o *spaceBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 	return spaceResourceType
 }
 
-func spaceResource(space client.Space) *v2.Resource {
+// spaceResource includes the API usage of the space in its profile.
+func spaceResource(space client.Space, usage []client.PeriodicUsage) *v2.Resource {
+	var groupOpts []resourceSdk.GroupTraitOption
+	if len(usage) > 0 {
+		profile := make(map[string]interface{})
+		addUsage(profile, usage)
+		groupOpts = append(groupOpts, resourceSdk.WithGroupProfile(profile))
+	}
+
 	spaceResource, err := resourceSdk.NewGroupResource(
 		space.Name,
 		spaceResourceType,
 		space.Sys.ID,
-		[]resourceSdk.GroupTraitOption{},
+		groupOpts,
+		resourceSdk.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: environmentResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: environmentAliasResourceType.Id},
+		),
 	)
 	if err != nil {
 		return nil
@@ -125,6 +268,12 @@ func (o *spaceBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 		}
 	}
 
+	// a new sync, its grants are of the space memberships as they are now
+	if pToken.Token == "" {
+		o.memberships.reset()
+		o.members.reset(ctx)
+	}
+
 	res, err := o.client.ListSpaces(ctx, offset)
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list users: %w", err)
@@ -135,9 +284,16 @@ func (o *spaceBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 	}
 	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
 
+	usage, err := o.usage.get(ctx, o.client.OrgID())
+	if err != nil {
+		return nil, "", nil, err
+	}
+
 	rv := []*v2.Resource{}
 	for _, space := range res.Items {
-		rv = append(rv, spaceResource(space))
+		rv = append(rv, spaceResource(space, usage[space.Sys.ID]))
+		// so the roles and members are ready by the time the grants of the space are synced
+		o.members.start(ctx, space.Sys.ID)
 	}
 
 	return rv, nextOffset, nil, nil
@@ -156,12 +312,19 @@ func (o *spaceBuilder) Entitlements(ctx context.Context, resource *v2.Resource,
 	rv := []*v2.Entitlement{}
 	// so it's only included once
 	if offset == 0 {
+		entitlementOpts := []entitlement.EntitlementOption{
+			entitlement.WithGrantableTo(userResourceType, invitationResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Admin for %s space", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Admin for %s space", resource.DisplayName)),
+		}
+		if rule := o.risk.classifySpaceAdmin(); rule != nil {
+			entitlementOpts = append(entitlementOpts, entitlement.WithAnnotation(riskAnnotation(rule)))
+		}
+
 		rv = append(rv, entitlement.NewAssignmentEntitlement(
 			resource,
 			spaceAdmin,
-			entitlement.WithGrantableTo(userResourceType),
-			entitlement.WithDescription(fmt.Sprintf("Admin for %s space", resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Admin for %s space", resource.DisplayName)),
+			entitlementOpts...,
 		))
 	}
 
@@ -175,12 +338,30 @@ func (o *spaceBuilder) Entitlements(ctx context.Context, resource *v2.Resource,
 	}
 
 	for _, role := range res.Items {
+		entitlementOpts := []entitlement.EntitlementOption{
+			entitlement.WithGrantableTo(userResourceType, invitationResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Role %s for %s space, %s", role.Name, resource.DisplayName, roleSummary(role))),
+			entitlement.WithDisplayName(fmt.Sprintf("Role %s for %s space ", role.Name, resource.DisplayName)),
+		}
+
+		if rule := o.risk.classifySpaceRole(role); rule != nil {
+			entitlementOpts = append(entitlementOpts, entitlement.WithAnnotation(riskAnnotation(rule)))
+		}
+
+		if baseline, ok := o.baseline[role.Name]; ok {
+			warnings, err := roleDrift(baseline, role)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			if len(warnings) > 0 {
+				entitlementOpts = append(entitlementOpts, entitlement.WithAnnotation(roleDriftAnnotation(warnings)))
+			}
+		}
+
 		rv = append(rv, entitlement.NewAssignmentEntitlement(
 			resource,
 			role.Name,
-			entitlement.WithGrantableTo(userResourceType),
-			entitlement.WithDescription(fmt.Sprintf("Role %s for %s space", role.Name, resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Role %s for %s space ", role.Name, resource.DisplayName)),
+			entitlementOpts...,
 		))
 	}
 
@@ -188,66 +369,101 @@ func (o *spaceBuilder) Entitlements(ctx context.Context, resource *v2.Resource,
 	return rv, nextOffset, nil, nil
 }
 
-func (o *spaceBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
-	var offset int
-	var err error
-	if pToken.Token != "" {
-		offset, err = strconv.Atoi(pToken.Token)
+// Grants returns every grant of the space in a single page, from the org wide listing of space memberships
+// prefetched when the space was listed.
+func (o *spaceBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	memberships, err := o.members.take(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	rv := []*v2.Grant{}
+	for _, spaceMembership := range memberships {
+		grants, err := o.membershipGrants(ctx, resource, spaceMembership)
 		if err != nil {
 			return nil, "", nil, err
 		}
+		rv = append(rv, grants...)
+	}
+	return rv, "", nil, nil
+}
+
+// principal returns the principal of the user's memberships, and whether the user is yet to accept their invitation.
+func (o *spaceBuilder) principal(ctx context.Context, userID string) (*v2.ResourceId, bool, error) {
+	if o.directory == nil {
+		principalID, err := resourceSdk.NewResourceID(userResourceType, userID)
+		return principalID, false, err
 	}
 
-	res, err := o.client.ListSpaceMembers(ctx, resource.Id.Resource, offset)
+	directory, err := o.directory(ctx)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list org memberships: %w", err)
+		return nil, false, err
 	}
 
-	if len(res.Items) == 0 {
-		return nil, "", nil, nil
+	return directory.principal(userID)
+}
+
+func (o *spaceBuilder) membershipGrants(ctx context.Context, resource *v2.Resource, spaceMembership client.SpaceMembership) ([]*v2.Grant, error) {
+	principalID, pending, err := o.principal(ctx, spaceMembership.Sys.User.Sys.ID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", spaceMembership.Sys.User.Sys.ID, err)
 	}
-	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
 
-	rv := []*v2.Grant{}
-	for _, spaceMembership := range res.Items {
-		principalID, err := resourceSdk.NewResourceID(userResourceType, spaceMembership.Sys.User.Sys.ID)
-		if err != nil {
-			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", spaceMembership.Sys.User.Sys.ID, err)
-		}
+	var grantOpts []grant.GrantOption
+	if pending {
+		grantOpts = append(grantOpts, grant.WithAnnotation(&contentfulv1.PendingInvitation{
+			Warning: pendingInvitationWarning,
+		}))
+	}
 
-		if spaceMembership.Admin {
-			principalID, err = resourceSdk.NewResourceID(userResourceType, spaceMembership.Sys.User.Sys.ID)
-			if err != nil {
-				return nil, "", nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", spaceMembership.Sys.User.Sys.ID, err)
-			}
-			rv = append(rv, grant.NewGrant(
+	if spaceMembership.Admin {
+		return []*v2.Grant{
+			grant.NewGrant(
 				resource,
 				spaceAdmin,
 				principalID,
-			))
-			continue
-		}
+				grantOpts...,
+			),
+		}, nil
+	}
 
-		for _, role := range spaceMembership.Roles {
-			roleName, err := o.cacheGetRoleName(ctx, resource.Id.Resource, role.Sys.ID)
-			if err != nil {
-				return nil, "", nil, fmt.Errorf("baton-contentful: failed to get role name for role ID %s: %w", role.Sys.ID, err)
-			}
-			rv = append(rv, grant.NewGrant(
-				resource,
-				roleName,
-				principalID,
-			))
+	rv := make([]*v2.Grant, 0, len(spaceMembership.Roles))
+	for _, link := range spaceMembership.Roles {
+		role, err := o.findRole(ctx, resource.Id.Resource, func(role client.Role) bool {
+			return role.Sys.ID == link.Sys.ID
+		})
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to get role name for role ID %s: %w", link.Sys.ID, err)
+		}
+		// deleted since the membership was listed, the rest of the space's grants are still good
+		if role == nil {
+			ctxzap.Extract(ctx).Warn("baton-contentful: skipping grant of unknown space role",
+				zap.String("space_id", resource.Id.Resource),
+				zap.String("role_id", link.Sys.ID),
+				zap.String("space_membership_id", spaceMembership.Sys.ID),
+			)
+			continue
 		}
+		rv = append(rv, grant.NewGrant(
+			resource,
+			role.Name,
+			principalID,
+			grantOpts...,
+		))
 	}
-	return rv, nextOffset, nil, nil
+	return rv, nil
 }
 
 func (o *spaceBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
 	spaceID := entitlement.Resource.Id.Resource
 	roleName := strings.Split(entitlement.Id, ":")[2]
 
-	resUser, err := o.client.GetUserByID(ctx, principal.Id.Resource)
+	// space memberships are created by email, which the invitation resource of an invitee doesn't have
+	if principal.Id.ResourceType == invitationResourceType.Id {
+		return nil, status.Errorf(codes.FailedPrecondition, "baton-contentful: %s hasn't accepted their org invitation yet", principal.Id.Resource)
+	}
+
+	resUser, err := o.client.SearchUsers(ctx, principal.Id.Resource)
 	if err != nil {
 		return nil, err
 	}
@@ -260,9 +476,19 @@ func (o *spaceBuilder) Grant(ctx context.Context, principal *v2.Resource, entitl
 
 	// admin role is special, we don't need to look it up
 	if roleName != spaceAdmin {
-		roleID, err = o.cacheGetRoleID(ctx, spaceID, roleName)
+		// the role may have been deleted since it was cached, make sure it still exists
+		roles, err := o.roles.refresh(ctx, spaceID, roleRefillInterval)
 		if err != nil {
-			return nil, fmt.Errorf("baton-contentful: failed to get role ID for role %s: %w", roleName, err)
+			return nil, fmt.Errorf("baton-contentful: failed to list roles of space %s: %w", spaceID, err)
+		}
+		for _, role := range roles {
+			if role.Name == roleName {
+				roleID = role.Sys.ID
+				break
+			}
+		}
+		if roleID == "" {
+			return nil, status.Errorf(codes.NotFound, "baton-contentful: role %s no longer exists in space %s", roleName, spaceID)
 		}
 	}
 
@@ -303,10 +529,47 @@ func (o *spaceBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations
 	return nil, nil
 }
 
-func newSpaceBuilder(client *client.Client) *spaceBuilder {
-	return &spaceBuilder{
-		client:         client,
-		mu:             &sync.Mutex{},
-		spaceRoleCache: make(map[string][]role),
+// Create creates a space named after the resource's display name, with the "defaultLocale" of its
+// group profile as default locale, en-US when unset.
+func (o *spaceBuilder) Create(ctx context.Context, resource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	name := strings.TrimSpace(resource.GetDisplayName())
+	if name == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-contentful: space name is required")
+	}
+
+	defaultLocale := defaultSpaceLocale
+	groupTrait, err := resourceSdk.GetGroupTrait(resource)
+	if err == nil {
+		if locale, ok := resourceSdk.GetProfileStringValue(groupTrait.GetProfile(), "defaultLocale"); ok && locale != "" {
+			defaultLocale = locale
+		}
+	}
+
+	space, err := o.client.CreateSpace(ctx, name, defaultLocale)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-contentful: failed to create space %s: %w", name, err)
+	}
+
+	return spaceResource(*space, nil), nil, nil
+}
+
+// Delete is required alongside Create, but deleting a space deletes all of its content for good.
+// That is left to Contentful itself.
+func (o *spaceBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	return nil, status.Errorf(codes.Unimplemented, "baton-contentful: spaces can't be deleted through the connector, delete space %s in Contentful", resourceId.Resource)
+}
+
+func newSpaceBuilder(client *client.Client, state *stateStore, maxConcurrency int, directory func(ctx context.Context) (*userDirectory, error), baseline roleBaseline, risk riskRules) *spaceBuilder {
+	o := &spaceBuilder{
+		client:    client,
+		state:     state,
+		directory: directory,
+		baseline:  baseline,
+		risk:      risk,
 	}
+	o.roles = newKeyedCache(roleCacheTTL, o.listRoles)
+	o.memberships = newKeyedCache(0, o.listOrgSpaceMemberships)
+	o.members = newPrefetcher(maxConcurrency, o.fetchMembers)
+	o.usage = newKeyedCache(usageCacheTTL, o.listUsage)
+	return o
 }
@@ -3,7 +3,9 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/conductorone/baton-contentful/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -12,6 +14,8 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const teamMembership = "member"
@@ -55,10 +59,10 @@ func (o *teamBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 	}
 
 	res, err := o.client.ListTeams(ctx, offset)
-	items := res.Items
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list users: %w", err)
+		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list teams: %w", err)
 	}
+	items := res.Items
 
 	if len(items) == 0 {
 		return nil, "", nil, nil
@@ -107,11 +111,16 @@ func (o *teamBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken
 	}
 	nextOffset := fmt.Sprintf("%d", offset+len(res.Items))
 
+	// the memberships of every team of the organization are listed
 	rv := []*v2.Grant{}
-	for _, orgMembership := range res.Items {
-		principalID, err := resourceSdk.NewResourceID(userResourceType, orgMembership.Sys.User.Sys.ID)
+	for _, membership := range res.Items {
+		if membership.Sys.Team.Sys.ID != resource.Id.Resource {
+			continue
+		}
+
+		principalID, err := resourceSdk.NewResourceID(userResourceType, membership.Sys.User.Sys.ID)
 		if err != nil {
-			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", orgMembership.Sys.User.Sys.ID, err)
+			return nil, "", nil, fmt.Errorf("baton-contentful: failed to create resource ID for user %v: %w", membership.Sys.User.Sys.ID, err)
 		}
 		rv = append(rv, grant.NewGrant(
 			resource,
@@ -161,11 +170,15 @@ func (o *teamBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 		return nil, fmt.Errorf("baton-contentful: failed to get team membership: %w", err)
 	}
 
-	if len(resTeamMembership.Items) == 0 {
+	// the user's memberships of every team are listed
+	i := slices.IndexFunc(resTeamMembership.Items, func(membership client.TeamMembership) bool {
+		return membership.Sys.Team.Sys.ID == teamID
+	})
+	if i < 0 {
 		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
 	}
 
-	teamMembershipID := resTeamMembership.Items[0].Sys.ID
+	teamMembershipID := resTeamMembership.Items[i].Sys.ID
 	err = o.client.DeleteTeamMembership(ctx, teamID, teamMembershipID)
 	if err != nil {
 		return nil, fmt.Errorf("baton-contentful: failed to delete team membership: %w", err)
@@ -173,6 +186,40 @@ func (o *teamBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 	return nil, nil
 }
 
+// Create creates a team named after the resource's display name. The description is taken from the
+// "description" of the group profile, as teamResource sets it, or the resource description.
+func (o *teamBuilder) Create(ctx context.Context, resource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	name := strings.TrimSpace(resource.GetDisplayName())
+	if name == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-contentful: team name is required")
+	}
+
+	description := resource.GetDescription()
+	groupTrait, err := resourceSdk.GetGroupTrait(resource)
+	if err == nil {
+		if profileDescription, ok := resourceSdk.GetProfileStringValue(groupTrait.GetProfile(), "description"); ok {
+			description = profileDescription
+		}
+	}
+
+	team, err := o.client.CreateTeam(ctx, name, description)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-contentful: failed to create team %s: %w", name, err)
+	}
+
+	return teamResource(*team), nil, nil
+}
+
+func (o *teamBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	err := o.client.DeleteTeam(ctx, resourceId.Resource)
+	switch status.Code(err) {
+	case codes.OK, codes.NotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("baton-contentful: failed to delete team %s: %w", resourceId.Resource, err)
+	}
+}
+
 func newTeamBuilder(client *client.Client) *teamBuilder {
 	return &teamBuilder{
 		client: client,
@@ -0,0 +1,125 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// usageUnavailable tells whether the usage endpoints refused the request, they are an alpha feature
+// not every organization can read.
+func usageUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.PermissionDenied:
+		return true
+	default:
+		return false
+	}
+}
+
+// usageProfileKey is the profile key of the usage of a metric, e.g. "usageCda".
+func usageProfileKey(metric string) string {
+	if metric == "" {
+		return "usage"
+	}
+	return "usage" + strings.ToUpper(metric[:1]) + metric[1:]
+}
+
+// addUsage adds the usages to the profile, with the date range they cover.
+func addUsage(profile map[string]interface{}, usages []client.PeriodicUsage) {
+	for _, usage := range usages {
+		profile[usageProfileKey(usage.Metric)] = usage.Usage
+		if usage.DateRange.StartAt != "" {
+			profile["usageStartAt"] = usage.DateRange.StartAt
+			profile["usageEndAt"] = usage.DateRange.EndAt
+		}
+	}
+}
+
+// orgUsage returns the profile fields of the usage of the client's organization: its seats by role, its spaces
+// and, where available, its API usage in the current billing period.
+func orgUsage(ctx context.Context, c *client.Client) (map[string]interface{}, error) {
+	rv := make(map[string]interface{})
+
+	seats, err := c.CountOrganizationMemberships(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to count org memberships: %w", err)
+	}
+	rv["seats"] = seats
+	for _, role := range orgRoles {
+		seats, err := c.CountOrganizationMembershipsByRole(ctx, role)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to count org memberships with role %s: %w", role, err)
+		}
+		rv[fmt.Sprintf("seats%s%s", strings.ToUpper(role[:1]), role[1:])] = seats
+	}
+
+	// the token can see spaces of other organizations too
+	var spaces int
+	var offset int
+	for {
+		res, err := c.ListSpaces(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list spaces: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, space := range res.Items {
+			if space.Sys.Org.Sys.ID == c.OrgID() {
+				spaces++
+			}
+		}
+
+		offset += len(res.Items)
+	}
+	rv["spaceCount"] = spaces
+
+	res, err := c.ListOrganizationPeriodicUsages(ctx)
+	switch {
+	case err == nil:
+		addUsage(rv, res.Items)
+	case usageUnavailable(err):
+		ctxzap.Extract(ctx).Debug("baton-contentful: organization API usage is unavailable", zap.Error(err))
+	default:
+		return nil, fmt.Errorf("baton-contentful: failed to get organization API usage: %w", err)
+	}
+
+	return rv, nil
+}
+
+// listSpaceUsage returns the API usage of the spaces of the client's organization by space ID, empty when unavailable.
+func listSpaceUsage(ctx context.Context, c *client.Client) (map[string][]client.PeriodicUsage, error) {
+	rv := make(map[string][]client.PeriodicUsage)
+	var offset int
+	for {
+		res, err := c.ListSpacePeriodicUsages(ctx, offset)
+		if usageUnavailable(err) {
+			ctxzap.Extract(ctx).Debug("baton-contentful: space API usage is unavailable", zap.Error(err))
+			return rv, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to list space API usage: %w", err)
+		}
+
+		if len(res.Items) == 0 {
+			break
+		}
+
+		for _, usage := range res.Items {
+			spaceID := usage.Sys.Space.Sys.ID
+			rv[spaceID] = append(rv[spaceID], usage)
+		}
+
+		offset += len(res.Items)
+	}
+	return rv, nil
+}
@@ -0,0 +1,50 @@
+package connector
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddUsage(t *testing.T) {
+	profile := map[string]interface{}{"seats": 3}
+	addUsage(profile, []client.PeriodicUsage{
+		{Metric: "cda", Usage: 1200, DateRange: client.DateRange{StartAt: "2024-01-01", EndAt: "2024-01-31"}},
+		{Metric: "gql", Usage: 7},
+	})
+
+	require.Equal(t, map[string]interface{}{
+		"seats":        3,
+		"usageCda":     int64(1200),
+		"usageGql":     int64(7),
+		"usageStartAt": "2024-01-01",
+		"usageEndAt":   "2024-01-31",
+	}, profile)
+}
+
+func TestSpacesFetchUsageAgainOnceExpired(t *testing.T) {
+	s := newFakeContentful(t)
+	o := newSpaceBuilder(s.Client(t), nil, 2, nil, nil, nil)
+	var fetches atomic.Int32
+	fetch := o.usage.fetch
+	o.usage.fetch = func(ctx context.Context, orgID string) (map[string][]client.PeriodicUsage, error) {
+		fetches.Add(1)
+		return fetch(ctx, orgID)
+	}
+	now := time.Now()
+	o.usage.now = func() time.Time {
+		return now
+	}
+
+	listResources(t, o, nil)
+	listResources(t, o, nil)
+	require.EqualValues(t, 1, fetches.Load())
+
+	now = now.Add(usageCacheTTL)
+	listResources(t, o, nil)
+	require.EqualValues(t, 2, fetches.Load())
+}
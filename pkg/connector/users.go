@@ -2,19 +2,35 @@ package connector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	contentfulv1 "github.com/conductorone/baton-contentful/pb/contentful/v1"
 	"github.com/conductorone/baton-contentful/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	orgMembershipActive  = "active"
+	orgMembershipPending = "pending"
+
+	userStatusPending = "pending"
 )
 
 type userBuilder struct {
 	client *client.Client
+	// used to resolve space role names for the initial access of created accounts
+	spaces *spaceBuilder
 }
 
 func (o *userBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -23,23 +39,46 @@ func (o *userBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 
 func (o *userBuilder) userResource(ctx context.Context, user client.User) *v2.Resource {
 	profile := map[string]interface{}{
-		"firstName":  user.FirstName,
-		"lastName":   user.LastName,
-		"email":      user.Email,
-		"2faEnabled": user.TwoFAEnabled,
+		"firstName":    user.FirstName,
+		"lastName":     user.LastName,
+		"email":        user.Email,
+		"2faEnabled":   user.TwoFAEnabled,
+		"signInCount":  user.SignInCount,
+		"signupSource": user.SignupSource,
 	}
 
+	membership := o.getOrgMembership(ctx, user.Sys.ID)
+	accountStatus, statusDetails := userStatus(user, membership)
+
 	traits := []resourceSdk.UserTraitOption{
 		resourceSdk.WithEmail(user.Email, true),
-		resourceSdk.WithUserProfile(profile),
 		resourceSdk.WithCreatedAt(user.Sys.CreatedAt),
+		resourceSdk.WithDetailedStatus(accountStatus, statusDetails),
+		resourceSdk.WithMFAStatus(&v2.UserTrait_MFAStatus{MfaEnabled: user.TwoFAEnabled}),
 	}
 
-	lastActive := o.client.GetLastActiveAt(ctx, user.Sys.ID)
-	if lastActive != nil {
-		traits = append(traits, resourceSdk.WithLastLogin(*lastActive))
+	if membership != nil {
+		profile["isExemptFromRestrictedMode"] = membership.IsExemptFromRestrictedMode
+
+		if membership.Sys.LastActiveAt != nil {
+			traits = append(traits, resourceSdk.WithLastLogin(*membership.Sys.LastActiveAt))
+		}
+
+		// users that never signed in through the identity provider log in with a password
+		sso := membership.Sys.SSO
+		profile["ssoEnabled"] = sso != nil
+		profile["loginMethod"] = "password"
+		if sso != nil {
+			profile["loginMethod"] = "sso"
+			if sso.LastSignInAt != nil {
+				profile["lastSsoLoginAt"] = sso.LastSignInAt.Format(time.RFC3339)
+			}
+		}
+		traits = append(traits, resourceSdk.WithSSOStatus(&v2.UserTrait_SSOStatus{SsoEnabled: sso != nil}))
 	}
 
+	traits = append(traits, resourceSdk.WithUserProfile(profile))
+
 	userResource, err := resourceSdk.NewUserResource(
 		fmt.Sprintf("%s %s", user.FirstName, user.LastName),
 		userResourceType,
@@ -66,10 +105,10 @@ func (o *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 	}
 
 	res, err := o.client.ListUsers(ctx, offset)
-	users := res.Items
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("baton-contentful: failed to list users: %w", err)
 	}
+	users := res.Items
 
 	if len(users) == 0 {
 		return nil, "", nil, nil
@@ -114,40 +153,307 @@ func (o *userBuilder) CreateAccount(ctx context.Context, accountInfo *v2.Account
 		return nil, nil, nil, err
 	}
 
+	// resolve the initial access before creating anything so that bad input doesn't leave a dangling invitation
+	access, err := o.getInitialAccess(ctx, accountInfo)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	invitation, err := o.client.CreateInvitation(ctx, body)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("baton-contentful: cannot create invitation: %w", err)
 	}
 
+	err = o.assignInitialAccess(ctx, invitation, body.Email, access)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	user, err := o.getInvitedUser(ctx, invitation, body.Email)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	annos := annotations.New(&contentfulv1.Invitation{
+		Id:                       invitation.Sys.ID,
+		InvitationUrl:            invitation.Sys.InvitationURL,
+		UserId:                   invitation.Sys.User.Sys.ID,
+		OrganizationMembershipId: invitation.Sys.OrganizationMembership.Sys.ID,
+	})
+
+	// the email already belongs to a user of the organization, so the account can be linked right away
+	if user != nil {
+		return &v2.CreateAccountResponse_SuccessResult{
+			Resource:              o.userResource(ctx, *user),
+			IsCreateAccountResult: true,
+		}, nil, annos, nil
+	}
+
 	return &v2.CreateAccountResponse_ActionRequiredResult{
-		Message: invitation.Sys.InvitationURL,
-	}, nil, nil, nil
+		Message:               fmt.Sprintf("invitation %s must be accepted: %s", invitation.Sys.ID, invitation.Sys.InvitationURL),
+		IsCreateAccountResult: true,
+	}, nil, annos, nil
+}
+
+// getInvitedUser returns the user of the organization the invitation was sent to, or nil if the email doesn't
+// belong to one yet. Users are only visible to the organizations they are members of, so someone who only has a
+// Contentful account in another organization is nil too until they accept the invitation.
+func (o *userBuilder) getInvitedUser(ctx context.Context, invitation *client.Invitation, email string) (*client.User, error) {
+	userID := invitation.Sys.User.Sys.ID
+	if userID != "" {
+		user, err := o.client.GetUser(ctx, userID)
+		switch status.Code(err) {
+		case codes.OK:
+			return user, nil
+		case codes.NotFound:
+			// not visible in this organization yet, fall back to searching by email
+		default:
+			return nil, fmt.Errorf("baton-contentful: failed to get invited user %s: %w", userID, err)
+		}
+	}
+
+	res, err := o.client.SearchUsers(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: failed to search users by email %s: %w", email, err)
+	}
+
+	for _, user := range res.Items {
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type spaceRoleAssignment struct {
+	spaceID string
+	roleID  string
+	admin   bool
+}
+
+type initialAccess struct {
+	teamIDs    []string
+	spaceRoles []spaceRoleAssignment
+}
+
+// getInitialAccess reads the optional teams and space roles from the account profile.
+// Space roles are given as "<spaceID>:<role name>", the "admin" role name grants space admin.
+func (o *userBuilder) getInitialAccess(ctx context.Context, accountInfo *v2.AccountInfo) (*initialAccess, error) {
+	pMap := accountInfo.Profile.AsMap()
+
+	teamIDs, err := getProfileStringList(pMap, "teamIds")
+	if err != nil {
+		return nil, err
+	}
+
+	spaceRoles, err := getProfileStringList(pMap, "spaceRoles")
+	if err != nil {
+		return nil, err
+	}
+
+	access := &initialAccess{
+		teamIDs: teamIDs,
+	}
+	for _, spaceRole := range spaceRoles {
+		spaceID, roleName, ok := strings.Cut(spaceRole, ":")
+		if !ok || spaceID == "" || roleName == "" {
+			return nil, fmt.Errorf("baton-contentful: invalid space role %q, expected <spaceID>:<role name>", spaceRole)
+		}
+
+		if roleName == spaceAdmin {
+			access.spaceRoles = append(access.spaceRoles, spaceRoleAssignment{spaceID: spaceID, admin: true})
+			continue
+		}
+
+		roleID, err := o.spaces.cacheGetRoleID(ctx, spaceID, roleName)
+		if err != nil {
+			return nil, fmt.Errorf("baton-contentful: failed to get role ID for role %s: %w", roleName, err)
+		}
+		access.spaceRoles = append(access.spaceRoles, spaceRoleAssignment{spaceID: spaceID, roleID: roleID})
+	}
+
+	return access, nil
+}
+
+// assignInitialAccess adds the invited user to the requested teams and spaces. If any step fails,
+// everything created so far, including the invitation, is removed again.
+func (o *userBuilder) assignInitialAccess(ctx context.Context, invitation *client.Invitation, email string, access *initialAccess) error {
+	var rollback []func() error
+	fail := func(cause error) error {
+		errs := []error{cause}
+		for i := len(rollback) - 1; i >= 0; i-- {
+			if err := rollback[i](); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	// invitations are backed by a pending org membership, deleting it revokes the invitation
+	orgMembershipID := invitation.Sys.OrganizationMembership.Sys.ID
+	if orgMembershipID != "" {
+		rollback = append(rollback, func() error {
+			if err := o.client.DeleteOrganizationMembership(ctx, orgMembershipID); err != nil {
+				return fmt.Errorf("baton-contentful: failed to revoke invitation %s: %w", invitation.Sys.ID, err)
+			}
+			return nil
+		})
+	}
+
+	if len(access.teamIDs) > 0 && orgMembershipID == "" {
+		return fail(fmt.Errorf("baton-contentful: invitation %s has no organization membership, cannot add it to teams", invitation.Sys.ID))
+	}
+
+	for _, teamID := range access.teamIDs {
+		teamMembership, err := o.client.CreateTeamMembership(ctx, teamID, orgMembershipID)
+		if err != nil {
+			return fail(fmt.Errorf("baton-contentful: failed to add invitation to team %s: %w", teamID, err))
+		}
+		rollback = append(rollback, func() error {
+			if err := o.client.DeleteTeamMembership(ctx, teamID, teamMembership.Sys.ID); err != nil {
+				return fmt.Errorf("baton-contentful: failed to delete team membership %s: %w", teamMembership.Sys.ID, err)
+			}
+			return nil
+		})
+	}
+
+	for _, spaceRole := range access.spaceRoles {
+		spaceMembership, err := o.client.CreateSpaceMembership(ctx, spaceRole.spaceID, email, spaceRole.roleID, spaceRole.admin)
+		if err != nil {
+			return fail(fmt.Errorf("baton-contentful: failed to add invitation to space %s: %w", spaceRole.spaceID, err))
+		}
+		rollback = append(rollback, func() error {
+			if err := o.client.DeleteSpaceMembership(ctx, spaceRole.spaceID, spaceMembership.Sys.ID); err != nil {
+				return fmt.Errorf("baton-contentful: failed to delete space membership %s: %w", spaceMembership.Sys.ID, err)
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func getProfileStringList(pMap map[string]interface{}, key string) ([]string, error) {
+	if pMap[key] == nil {
+		return nil, nil
+	}
+
+	values, ok := pMap[key].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("baton-contentful: %s must be a list of strings", key)
+	}
+
+	rv := make([]string, 0, len(values))
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("baton-contentful: %s must be a list of strings", key)
+		}
+		str = strings.TrimSpace(str)
+		if str != "" {
+			rv = append(rv, str)
+		}
+	}
+
+	return rv, nil
+}
+
+// userStatus maps the user's activation state and org membership status to a trait status.
+// Pending users (invited but never activated, or with an unconfirmed email) can't sign in yet,
+// so they are reported as disabled with a "pending" detail.
+func userStatus(user client.User, membership *client.OrganizationMembership) (v2.UserTrait_Status_Status, string) {
+	if membership != nil && membership.Sys.Status != "" && membership.Sys.Status != orgMembershipActive {
+		if membership.Sys.Status == orgMembershipPending {
+			return v2.UserTrait_Status_STATUS_DISABLED, userStatusPending
+		}
+		return v2.UserTrait_Status_STATUS_DISABLED, membership.Sys.Status
+	}
+
+	if !user.Activated || !user.Confirmed {
+		return v2.UserTrait_Status_STATUS_DISABLED, userStatusPending
+	}
+
+	return v2.UserTrait_Status_STATUS_ENABLED, orgMembershipActive
+}
+
+// getOrgMembership returns nil if the membership can't be fetched, the user is
+// still synced without the membership details.
+func (o *userBuilder) getOrgMembership(ctx context.Context, userID string) *client.OrganizationMembership {
+	res, err := o.client.GetOrganizationMembershipByUser(ctx, userID)
+	if err != nil {
+		return nil
+	}
+
+	if len(res.Items) == 0 {
+		return nil
+	}
+
+	return &res.Items[0]
 }
 
 func getCreateInvitationBody(accountInfo *v2.AccountInfo) (*client.CreateInvitationBody, error) {
 	pMap := accountInfo.Profile.AsMap()
-	firstName := ""
-	lastName := ""
-	role := ""
-	if pMap["firstName"] != nil {
-		firstName = pMap["firstName"].(string)
+
+	email := strings.TrimSpace(accountInfo.Login)
+	if email == "" {
+		profileEmail, err := getProfileString(pMap, "email")
+		if err != nil {
+			return nil, err
+		}
+		email = profileEmail
 	}
-	if pMap["lastName"] != nil {
-		lastName = pMap["lastName"].(string)
+	if email == "" {
+		return nil, fmt.Errorf("baton-contentful: email is required to create an invitation")
 	}
-	if pMap["role"] != nil {
-		role = pMap["role"].(string)
+
+	firstName, err := getProfileString(pMap, "firstName")
+	if err != nil {
+		return nil, err
 	}
+
+	lastName, err := getProfileString(pMap, "lastName")
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := getProfileString(pMap, "role")
+	if err != nil {
+		return nil, err
+	}
+
+	role = strings.ToLower(role)
+	if role == "" {
+		role = orgMember
+	}
+	if !slices.Contains(orgRoles, role) {
+		return nil, fmt.Errorf("baton-contentful: invalid role %q, must be one of: %s", role, strings.Join(orgRoles, ", "))
+	}
+
 	return &client.CreateInvitationBody{
-		Email:     accountInfo.Login,
+		Email:     email,
 		FirstName: firstName,
 		LastName:  lastName,
 		Role:      role,
 	}, nil
 }
 
-func newUserBuilder(client *client.Client) *userBuilder {
+func getProfileString(pMap map[string]interface{}, key string) (string, error) {
+	if pMap[key] == nil {
+		return "", nil
+	}
+
+	value, ok := pMap[key].(string)
+	if !ok {
+		return "", fmt.Errorf("baton-contentful: %s must be a string, got %T", key, pMap[key])
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+func newUserBuilder(client *client.Client, spaces *spaceBuilder) *userBuilder {
 	return &userBuilder{
 		client: client,
+		spaces: spaces,
 	}
 }
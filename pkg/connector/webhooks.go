@@ -0,0 +1,60 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/conductorone/baton-contentful/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+// https://www.contentful.com/developers/docs/webhooks/overview/
+const (
+	topicSpaceMembershipCreate = "ContentManagement.SpaceMembership.create"
+	topicSpaceMembershipSave   = "ContentManagement.SpaceMembership.save"
+	topicSpaceMembershipDelete = "ContentManagement.SpaceMembership.delete"
+)
+
+// WebhookEvents normalizes a Contentful webhook payload into grant and revoke events.
+// Only space membership topics produce events, every other topic is ignored.
+func (d *Connector) WebhookEvents(ctx context.Context, topic string, body []byte) ([]*v2.Event, error) {
+	switch topic {
+	case topicSpaceMembershipCreate, topicSpaceMembershipSave, topicSpaceMembershipDelete:
+	default:
+		return nil, nil
+	}
+
+	var spaceMembership client.SpaceMembership
+	err := json.Unmarshal(body, &spaceMembership)
+	if err != nil {
+		return nil, fmt.Errorf("baton-contentful: invalid space membership payload: %w", err)
+	}
+
+	if spaceMembership.Sys.ID == "" || spaceMembership.Sys.Space.Sys.ID == "" || spaceMembership.Sys.User.Sys.ID == "" {
+		return nil, fmt.Errorf("baton-contentful: space membership payload is missing its id, space or user")
+	}
+
+	grants, err := spaceMembershipGrants(ctx, newSpaceBuilder(d.client, nil, d.maxConcurrency, d.directory.getRecent, nil, nil), spaceMembership)
+	if err != nil {
+		return nil, err
+	}
+
+	occurredAt := spaceMembership.Sys.UpdatedAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	key := "webhook:space_membership:" + spaceMembership.Sys.ID
+	events := make([]*v2.Event, 0, len(grants))
+	for _, g := range grants {
+		if topic == topicSpaceMembershipDelete {
+			events = append(events, newRevokeEvent(fmt.Sprintf("%s:deleted", key), g, occurredAt))
+			continue
+		}
+		events = append(events, newGrantEvent(key, spaceMembership.Sys.Version, g, occurredAt))
+	}
+
+	return events, nil
+}
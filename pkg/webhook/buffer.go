@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Buffer is an append only file of events, one JSON encoded event per line.
+// The receiver appends to it and the connector reads from it in ListEvents, using the byte offset as the cursor.
+type Buffer struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewBuffer(path string) *Buffer {
+	return &Buffer{
+		path: path,
+	}
+}
+
+func (b *Buffer) Append(events []*v2.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var data bytes.Buffer
+	for _, event := range events {
+		line, err := protojson.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("webhook: failed to marshal event %s: %w", event.Id, err)
+		}
+		data.Write(line)
+		data.WriteByte('\n')
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, err := os.OpenFile(b.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("webhook: failed to open buffer: %w", err)
+	}
+	defer f.Close()
+
+	// a single write per batch so a concurrent reader never sees half of it
+	_, err = f.Write(data.Bytes())
+	if err != nil {
+		return fmt.Errorf("webhook: failed to write buffer: %w", err)
+	}
+
+	return nil
+}
+
+// Read returns up to limit events starting at offset, and the offset to continue from.
+// A limit of 0 or less reads everything. A missing buffer file has no events.
+func (b *Buffer) Read(offset int64, limit int) ([]*v2.Event, int64, error) {
+	f, err := os.Open(b.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, offset, nil
+		}
+		return nil, offset, fmt.Errorf("webhook: failed to open buffer: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, offset, fmt.Errorf("webhook: failed to seek buffer: %w", err)
+	}
+
+	var rv []*v2.Event
+	reader := bufio.NewReader(f)
+	for limit <= 0 || len(rv) < limit {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// a line without a newline is still being written, it is read on the next call
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, offset, fmt.Errorf("webhook: failed to read buffer: %w", err)
+		}
+
+		event := &v2.Event{}
+		err = protojson.Unmarshal(line, event)
+		if err != nil {
+			return nil, offset, fmt.Errorf("webhook: invalid event at offset %d: %w", offset, err)
+		}
+
+		rv = append(rv, event)
+		offset += int64(len(line))
+	}
+
+	return rv, offset, nil
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuffer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	buffer := NewBuffer(path)
+
+	events, offset, err := buffer.Read(0, 0)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.Zero(t, offset)
+
+	require.NoError(t, buffer.Append([]*v2.Event{{Id: "1"}, {Id: "2"}}))
+	require.NoError(t, buffer.Append([]*v2.Event{{Id: "3"}}))
+
+	events, offset, err = buffer.Read(0, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "1", events[0].Id)
+	require.Equal(t, "2", events[1].Id)
+
+	events, offset, err = buffer.Read(offset, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "3", events[0].Id)
+
+	// a partially written line is left for the next read
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"4"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	events, next, err := buffer.Read(offset, 0)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.Equal(t, offset, next)
+}
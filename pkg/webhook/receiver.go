@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// maxBodySize is well above the size of the membership payloads Contentful sends.
+const maxBodySize = 1 << 20
+
+// Normalizer turns a webhook payload for the given topic into connector events.
+// Topics that aren't relevant return no events.
+type Normalizer func(ctx context.Context, topic string, body []byte) ([]*v2.Event, error)
+
+// Receiver is an http.Handler that verifies Contentful webhooks, normalizes them and buffers the resulting events.
+type Receiver struct {
+	verifier  *Verifier
+	normalize Normalizer
+	buffer    *Buffer
+}
+
+func NewReceiver(verifier *Verifier, normalize Normalizer, buffer *Buffer) *Receiver {
+	return &Receiver{
+		verifier:  verifier,
+		normalize: normalize,
+		buffer:    buffer,
+	}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	l := ctxzap.Extract(ctx)
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+
+	err = r.verifier.Verify(req, body)
+	if err != nil {
+		l.Warn("baton-contentful: rejected webhook", zap.Error(err))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	topic := req.Header.Get(TopicHeader)
+	if topic == "" {
+		http.Error(w, "missing topic", http.StatusBadRequest)
+		return
+	}
+
+	events, err := r.normalize(ctx, topic, body)
+	if err != nil {
+		l.Error("baton-contentful: failed to normalize webhook", zap.String("topic", topic), zap.Error(err))
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	err = r.buffer.Append(events)
+	if err != nil {
+		l.Error("baton-contentful: failed to buffer webhook events", zap.String("topic", topic), zap.Error(err))
+		http.Error(w, "failed to buffer events", http.StatusInternalServerError)
+		return
+	}
+
+	l.Debug("baton-contentful: buffered webhook events", zap.String("topic", topic), zap.Int("events", len(events)))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// echoNormalizer emits one event per payload, identified by the payload's sys.id.
+func echoNormalizer(_ context.Context, topic string, body []byte) ([]*v2.Event, error) {
+	var payload struct {
+		Sys struct {
+			ID string `json:"id"`
+		} `json:"sys"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return []*v2.Event{{Id: topic + ":" + payload.Sys.ID}}, nil
+}
+
+func TestReceiver(t *testing.T) {
+	verifier := NewVerifier(testSecret)
+	buffer := NewBuffer(filepath.Join(t.TempDir(), "events.jsonl"))
+	receiver := NewReceiver(verifier, echoNormalizer, buffer)
+
+	testCases := []struct {
+		name       string
+		fixture    string
+		topic      string
+		tamper     bool
+		wantStatus int
+		wantEvent  string
+	}{
+		{
+			name:       "space membership created",
+			fixture:    "space_membership_create.json",
+			topic:      "ContentManagement.SpaceMembership.create",
+			wantStatus: http.StatusNoContent,
+			wantEvent:  "ContentManagement.SpaceMembership.create:0Ma1nGS7F4WSiNbNCvpCqv",
+		},
+		{
+			name:       "space membership deleted",
+			fixture:    "space_membership_delete.json",
+			topic:      "ContentManagement.SpaceMembership.delete",
+			wantStatus: http.StatusNoContent,
+			wantEvent:  "ContentManagement.SpaceMembership.delete:0Ma1nGS7F4WSiNbNCvpCqv",
+		},
+		{
+			name:       "invalid signature",
+			fixture:    "space_membership_create.json",
+			topic:      "ContentManagement.SpaceMembership.create",
+			tamper:     true,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	var offset int64
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			require.NoError(t, err)
+
+			req := signedRequest(t, verifier, string(body), time.Now())
+			req.Header.Set(TopicHeader, tc.topic)
+			req.Header.Set(SignatureHeader, verifier.Sign(req.Method, req.URL.RequestURI(), signedHeaders(req), body))
+			if tc.tamper {
+				req.Header.Set(SignatureHeader, verifier.Sign(req.Method, req.URL.RequestURI(), signedHeaders(req), []byte("{}")))
+			}
+
+			rec := httptest.NewRecorder()
+			receiver.ServeHTTP(rec, req)
+			require.Equal(t, tc.wantStatus, rec.Code)
+
+			events, next, err := buffer.Read(offset, 0)
+			require.NoError(t, err)
+			offset = next
+			if tc.wantEvent == "" {
+				require.Empty(t, events)
+				return
+			}
+			require.Len(t, events, 1)
+			require.Equal(t, tc.wantEvent, events[0].Id)
+		})
+	}
+}
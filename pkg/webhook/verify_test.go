@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "s3cr3t"
+
+// signedRequest builds a request signed the way Contentful signs webhooks.
+func signedRequest(t *testing.T, verifier *Verifier, body string, at time.Time) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks?source=contentful", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set(TopicHeader, "ContentManagement.SpaceMembership.create")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(at.UnixMilli(), 10))
+	req.Header.Set(SignedHeadersHeader, "x-contentful-signed-headers,x-contentful-timestamp,x-contentful-topic")
+	req.Header.Set(SignatureHeader, verifier.Sign(req.Method, req.URL.RequestURI(), signedHeaders(req), []byte(body)))
+
+	return req
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2025, 3, 4, 10, 21, 9, 0, time.UTC)
+	verifier := NewVerifier(testSecret)
+	verifier.now = func() time.Time { return now }
+
+	testCases := []struct {
+		name    string
+		request func() *http.Request
+		body    string
+		wantErr bool
+	}{
+		{
+			name:    "valid signature",
+			request: func() *http.Request { return signedRequest(t, verifier, `{"sys":{}}`, now) },
+			body:    `{"sys":{}}`,
+		},
+		{
+			name:    "tampered body",
+			request: func() *http.Request { return signedRequest(t, verifier, `{"sys":{}}`, now) },
+			body:    `{"sys":{"id":"x"}}`,
+			wantErr: true,
+		},
+		{
+			name: "tampered signed header",
+			request: func() *http.Request {
+				req := signedRequest(t, verifier, `{"sys":{}}`, now)
+				req.Header.Set(TopicHeader, "ContentManagement.SpaceMembership.delete")
+				return req
+			},
+			body:    `{"sys":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			request: func() *http.Request { return signedRequest(t, verifier, `{"sys":{}}`, now.Add(-time.Minute)) },
+			body:    `{"sys":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "dated in the future",
+			request: func() *http.Request { return signedRequest(t, verifier, `{"sys":{}}`, now.Add(time.Minute)) },
+			body:    `{"sys":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "clock slightly ahead",
+			request: func() *http.Request { return signedRequest(t, verifier, `{"sys":{}}`, now.Add(10*time.Second)) },
+			body:    `{"sys":{}}`,
+		},
+		{
+			name: "wrong secret",
+			request: func() *http.Request {
+				return signedRequest(t, NewVerifier("other"), `{"sys":{}}`, now)
+			},
+			body:    `{"sys":{}}`,
+			wantErr: true,
+		},
+		{
+			name: "missing signature",
+			request: func() *http.Request {
+				req := signedRequest(t, verifier, `{"sys":{}}`, now)
+				req.Header.Del(SignatureHeader)
+				return req
+			},
+			body:    `{"sys":{}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifier.Verify(tc.request(), []byte(tc.body))
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrInvalidSignature)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}